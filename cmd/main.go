@@ -2,65 +2,488 @@ package main
 
 import (
 	"context"
+	"errors"
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/digest"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/escalation"
+	"github.com/Unitazavr/AvitoPR/internal/events"
+	"github.com/Unitazavr/AvitoPR/internal/forge"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi"
 	"github.com/Unitazavr/AvitoPR/internal/http"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/migrations"
+	"github.com/Unitazavr/AvitoPR/internal/notify"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/reminder"
 	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/Unitazavr/AvitoPR/internal/webhook"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
-	"log"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"log/slog"
+	"net"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	//Часовые пояса команд и пользователей, в образе alpine нет tzdata
+	_ "time/tzdata"
 )
 
 func main() {
+	root := &cobra.Command{
+		Use:           "server",
+		Short:         "AvitoPR reviewer assignment service",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			setupEnv()
+		},
+		//Без подкоманды запускается сервер, как и раньше
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(false)
+		},
+	}
+	root.AddCommand(newServeCmd(), newMigrateCmd())
+
+	if err := root.Execute(); err != nil {
+		slog.Error("command failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// setupEnv загружает .env и настраивает логи
+func setupEnv() {
 	//Конфиги
 
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("No .env file found, using system environment variables")
+	envErr := godotenv.Load()
+
+	//Логи в JSON, уровень задаётся LOG_LEVEL
+	slog.SetDefault(logger.New(os.Stdout, os.Getenv("LOG_LEVEL")))
+
+	if envErr != nil {
+		slog.Info("No .env file found, using system environment variables")
+	}
+}
+
+func newServeCmd() *cobra.Command {
+	var migrateOnStart bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP and gRPC servers",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(migrateOnStart)
+		},
 	}
+	cmd.Flags().BoolVar(&migrateOnStart, "migrate", false, "apply pending migrations before starting")
+	return cmd
+}
+
+// postgresDSN возвращает POSTGRES_DSN
+func postgresDSN() (string, error) {
 	dsn := os.Getenv("POSTGRES_DSN")
 	if dsn == "" {
-		log.Fatal("POSTGRES_DSN is required (e.g. postgres://user:pass@db:5432/dbname?sslmode=disable)")
+		return "", errors.New("POSTGRES_DSN is required (e.g. postgres://user:pass@db:5432/dbname?sslmode=disable)")
+	}
+	return dsn, nil
+}
+
+// serve запускает сервис; migrateOnStart применяет миграции до подключения пула
+func serve(migrateOnStart bool) {
+	appLogger := slog.Default()
+
+	dsn, err := postgresDSN()
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//Трейсинг: TRACING_EXPORTER=otlp|stdout, по умолчанию выключен
+	shutdownTracing, err := tracing.Setup(ctx, os.Getenv("TRACING_EXPORTER"))
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			slog.Error("tracing shutdown failed", "error", err)
+		}
+	}()
+
 	//Подключение к БД
-	pool, err := pgxpool.New(context.Background(), dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		slog.Error("invalid POSTGRES_DSN", "error", err)
+		os.Exit(1)
+	}
+	//Схема должна быть не старше встроенных миграций, иначе /health/ready отвечает 503
+	schemaVersion, err := migrations.Latest()
 	if err != nil {
-		log.Fatalf("failed to create pgx pool: %v", err)
+		slog.Error("invalid embedded migrations", "error", err)
+		os.Exit(1)
+	}
+	if migrateOnStart {
+		if err := migrateUp(poolConfig.ConnConfig.Copy()); err != nil {
+			slog.Error("migrations failed", "error", err)
+			os.Exit(1)
+		}
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		slog.Error("failed to create pgx pool", "error", err)
+		os.Exit(1)
 	}
 	defer pool.Close()
+	metrics.RegisterPool(pool)
 
 	userRepo := repository.NewUserRepo(pool)
 	teamRepo := repository.NewTeamRepo(pool)
 	prRepo := repository.NewPrRepo(pool)
+	outboxRepo := repository.NewOutboxRepo(pool)
+	webhookRepo := repository.NewWebhookRepo(pool)
+	forgeRepo := repository.NewForgeRepo(pool)
+	tokenRepo := repository.NewTokenRepo(pool)
+	idempotencyRepo := repository.NewIdempotencyRepo(pool)
+	reminderRepo := repository.NewReminderRepo(pool)
+	scheduleRepo := repository.NewScheduleRepo(pool)
+	notificationRepo := repository.NewNotificationRepo(pool)
+	digestRepo := repository.NewDigestRepo(pool)
+	statsRepo := repository.NewStatsRepo(pool)
+	exportRepo := repository.NewExportRepo(pool)
+	importRepo := repository.NewImportRepo(pool)
+	schemaRepo := repository.NewSchemaRepo(pool)
+
+	//Доменные события: outbox -> шина подписчиков
+	bus := events.NewBus()
+	bus.Subscribe("metrics", metrics.HandleEvent, domain.EventPRCreated, domain.EventPRMerged, domain.EventReviewerReassigned)
+	//Событие повторяется только отказавшим подписчикам, после 10 попыток оно мёртвое
+	dispatcher := events.NewDispatcher(outboxRepo, bus, time.Second, 100, 10, time.Second)
+	//Раздача событий подключённым клиентам (стрим gRPC и SSE) после фиксации доставки
+	broadcaster := events.NewBroadcaster(256)
+	dispatcher.AfterDispatch(broadcaster.Broadcast)
+	go dispatcher.Run(ctx)
+
+	//Исходящие вебхуки
+	webhookSender := webhook.NewSender(webhookRepo, &nethttp.Client{Timeout: 10 * time.Second}, 2*time.Second, 8, 10*time.Second)
+	bus.Subscribe("webhooks", webhookSender.Enqueue)
+	go webhookSender.Run(ctx)
+
+	//Уведомления ревьюверам: почта через SMTP_ADDR (без него почта не отправляется),
+	//входящие вебхуки Slack/Mattermost только на хосты из CHAT_WEBHOOK_HOSTS (через запятую);
+	//шаблоны можно переопределить в NOTIFICATION_TEMPLATES_DIR
+	notificationTemplates, err := notify.LoadTemplates(os.Getenv("NOTIFICATION_TEMPLATES_DIR"))
+	if err != nil {
+		slog.Error("failed to load notification templates", "error", err)
+		os.Exit(1)
+	}
+	chatHosts := notify.ParseChatHosts(getEnv("CHAT_WEBHOOK_HOSTS", "hooks.slack.com"))
+	chatHTTPClient := notify.NewChatHTTPClient(10 * time.Second)
+	notificationChannels := map[domain.NotificationChannel]notify.Channel{
+		domain.NotificationSlack:      notify.NewSlackChannel(chatHTTPClient, chatHosts),
+		domain.NotificationMattermost: notify.NewMattermostChannel(chatHTTPClient, chatHosts),
+	}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		notificationChannels[domain.NotificationEmail] = notify.NewEmailChannel(notify.SMTPConfig{
+			Addr:     addr,
+			From:     getEnv("SMTP_FROM", "avitopr@localhost"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	}
+	notifier := notify.NewNotifier(notificationRepo, prRepo, notificationTemplates, notificationChannels, 2*time.Second, 8, 30*time.Second)
+	bus.Subscribe("notifications", notifier.Enqueue, domain.EventReviewerAssigned, domain.EventReviewerReassigned)
+	go notifier.Run(ctx)
+
+	//Ежедневные сводки открытых ревью: проверка раз в DIGEST_CHECK_INTERVAL (0 - выключены);
+	//PR без связи с GitHub/GitLab получают ссылку по PR_URL_TEMPLATE ({id} - идентификатор PR)
+	digestInterval, err := getDuration("DIGEST_CHECK_INTERVAL", "1m")
+	if err != nil {
+		slog.Error("invalid DIGEST_CHECK_INTERVAL", "error", err)
+		os.Exit(1)
+	}
+	if digestInterval > 0 {
+		digestJob := digest.NewJob(digestRepo, userRepo, notifier, digestInterval, os.Getenv("PR_URL_TEMPLATE"))
+		go digestJob.Run(ctx)
+	}
+
+	//Передача ревьюверов на GitHub/GitLab, включается заданным токеном
+	forgeClients := map[domain.Forge]forge.Client{}
+	forgeHTTPClient := &nethttp.Client{Timeout: 10 * time.Second}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		forgeClients[domain.ForgeGitHub] = forge.NewGitHubClient(getEnv("GITHUB_API_URL", "https://api.github.com"), token, forgeHTTPClient)
+	}
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		forgeClients[domain.ForgeGitLab] = forge.NewGitLabClient(getEnv("GITLAB_API_URL", "https://gitlab.com"), token, forgeHTTPClient)
+	}
+	if len(forgeClients) > 0 {
+		syncer := forge.NewSyncer(forgeRepo, prRepo, forgeClients)
+		bus.Subscribe("forge-sync", syncer.Handle, domain.EventPRCreated, domain.EventReviewerReassigned)
+	}
+	//Напоминания о зависших PR: проверка раз в REMINDER_CHECK_INTERVAL (0 - выключены),
+	//PR старше REMINDER_THRESHOLD (если у команды не задан свой порог),
+	//одному ревьюверу не чаще раза в REMINDER_REPEAT
+	var reminderScheduler service.ReminderScheduler
+	reminderInterval, err := getDuration("REMINDER_CHECK_INTERVAL", "5m")
+	if err != nil {
+		slog.Error("invalid REMINDER_CHECK_INTERVAL", "error", err)
+		os.Exit(1)
+	}
+	if reminderInterval > 0 {
+		reminderThreshold, err := getDuration("REMINDER_THRESHOLD", "48h")
+		if err != nil || reminderThreshold <= 0 {
+			slog.Error("invalid REMINDER_THRESHOLD", "value", os.Getenv("REMINDER_THRESHOLD"))
+			os.Exit(1)
+		}
+		reminderRepeat, err := getDuration("REMINDER_REPEAT", "24h")
+		if err != nil || reminderRepeat <= 0 {
+			slog.Error("invalid REMINDER_REPEAT", "value", os.Getenv("REMINDER_REPEAT"))
+			os.Exit(1)
+		}
+		scheduler := reminder.NewScheduler(reminderRepo, scheduleRepo, notifier, reminderInterval, reminderThreshold, reminderRepeat)
+		go scheduler.Run(ctx)
+		reminderScheduler = scheduler
+	}
+
+	//Эскалация ревью, превысивших SLA команды: проверка раз в SLA_CHECK_INTERVAL (0 - выключена)
+	slaInterval, err := getDuration("SLA_CHECK_INTERVAL", "5m")
+	if err != nil {
+		slog.Error("invalid SLA_CHECK_INTERVAL", "error", err)
+		os.Exit(1)
+	}
+	if slaInterval > 0 {
+		escalator := escalation.NewEscalator(prRepo, scheduleRepo, slaInterval)
+		go escalator.Run(ctx)
+	}
+
 	//Джин
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.New()
 	router.Use(gin.Recovery())
-	router.Use(gin.Logger())
-	router.Use(cors.New(cors.Config{
-		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(metrics.Middleware())
+	router.Use(http.LoggingMiddleware(appLogger))
+	//CORS: токен передаётся в заголовке, cookies не используются,
+	//поэтому credentials разрешаются только для явно заданных origin
+	corsConfig := cors.Config{
+		AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "Idempotency-Key"},
+		ExposeHeaders: []string{"Content-Length", "X-Request-ID", "Idempotent-Replayed"},
+	}
+	if origins := splitList(os.Getenv("CORS_ALLOWED_ORIGINS")); len(origins) > 0 {
+		corsConfig.AllowOrigins = origins
+		corsConfig.AllowCredentials = true
+	} else {
+		corsConfig.AllowAllOrigins = true
+	}
+	router.Use(cors.New(corsConfig))
+
+	adminTokens := splitList(os.Getenv("ADMIN_TOKENS"))
+	if len(adminTokens) == 0 {
+		slog.Warn("ADMIN_TOKENS is empty, only tokens stored in the database are accepted")
+	}
+
+	//JWT шлюза: JWT_JWKS - путь к файлу или URL набора ключей
+	var jwtVerifier service.TokenVerifier
+	if jwks := os.Getenv("JWT_JWKS"); jwks != "" {
+		keys, err := auth.NewKeySet(ctx, jwks, &nethttp.Client{Timeout: 10 * time.Second}, time.Hour)
+		if err != nil {
+			slog.Error("failed to load JWKS", "source", jwks, "error", err)
+			os.Exit(1)
+		}
+		jwtVerifier = auth.NewJWTVerifier(keys, auth.JWTConfig{
+			Issuer:    os.Getenv("JWT_ISSUER"),
+			Audience:  os.Getenv("JWT_AUDIENCE"),
+			UserClaim: os.Getenv("JWT_USER_CLAIM"),
+			RoleClaim: os.Getenv("JWT_ROLE_CLAIM"),
+			AdminRole: os.Getenv("JWT_ADMIN_ROLE"),
+		})
+	}
+	//Лимиты частоты: RATE_LIMIT_<GROUP>=<запросов в секунду>:<burst>, 0 - без лимита
+	rateLimits := map[string]ratelimit.Limit{}
+	for group, env := range map[string]string{
+		"/team":        "RATE_LIMIT_TEAM",
+		"/users":       "RATE_LIMIT_USERS",
+		"/pullRequest": "RATE_LIMIT_PULLREQUEST",
+	} {
+		limit, err := ratelimit.ParseLimit(getEnv(env, "10:20"))
+		if err != nil {
+			slog.Error("invalid rate limit", "env", env, "error", err)
+			os.Exit(1)
+		}
+		rateLimits[group] = limit
+	}
+
+	//Idempotency-Key: ответы хранятся IDEMPOTENCY_TTL, истёкшие удаляются раз в час
+	idempotencyTTL, err := getDuration("IDEMPOTENCY_TTL", "24h")
+	if err != nil || idempotencyTTL <= 0 {
+		slog.Error("invalid IDEMPOTENCY_TTL", "value", os.Getenv("IDEMPOTENCY_TTL"))
+		os.Exit(1)
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n, err := idempotencyRepo.Purge(ctx); err != nil {
+					slog.Error("idempotency keys purge failed", "error", err)
+				} else if n > 0 {
+					slog.Debug("idempotency keys purged", "count", n)
+				}
+			}
+		}
+	}()
+
+	//Ответ /stats/turnaround кэшируется на STATS_CACHE_TTL (0 - без кэша)
+	statsCacheTTL, err := getDuration("STATS_CACHE_TTL", "5m")
+	if err != nil || statsCacheTTL < 0 {
+		slog.Error("invalid STATS_CACHE_TTL", "value", os.Getenv("STATS_CACHE_TTL"))
+		os.Exit(1)
+	}
+
 	//Роутинг, создание сервисов и контроллеров
-	http.RegisterRoutes(router, userRepo, teamRepo, prRepo)
+	http.RegisterRoutes(router, http.Deps{
+		UserRepo:            userRepo,
+		TeamRepo:            teamRepo,
+		PrRepo:              prRepo,
+		WebhookRepo:         webhookRepo,
+		ForgeRepo:           forgeRepo,
+		TokenRepo:           tokenRepo,
+		OutboxRepo:          outboxRepo,
+		Broadcaster:         broadcaster,
+		ReminderRepo:        reminderRepo,
+		ScheduleRepo:        scheduleRepo,
+		NotificationRepo:    notificationRepo,
+		DigestRepo:          digestRepo,
+		ChatWebhookHosts:    chatHosts,
+		StatsRepo:           statsRepo,
+		StatsCacheTTL:       statsCacheTTL,
+		ExportRepo:          exportRepo,
+		ImportRepo:          importRepo,
+		ReminderScheduler:   reminderScheduler,
+		SchemaRepo:          schemaRepo,
+		SchemaVersion:       schemaVersion,
+		IdempotencyRepo:     idempotencyRepo,
+		IdempotencyTTL:      idempotencyTTL,
+		AdminTokens:         adminTokens,
+		JWTVerifier:         jwtVerifier,
+		RateLimits:          rateLimits,
+		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitLabWebhookToken:  os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+	})
+
+	//gRPC API на GRPC_PORT, те же сервисы и права, что у HTTP
+	grpcServer := grpcapi.NewServer(appLogger, grpcapi.Deps{
+		UserRepo:    userRepo,
+		TeamRepo:    teamRepo,
+		PrRepo:      prRepo,
+		TokenRepo:   tokenRepo,
+		AdminTokens: adminTokens,
+		JWTVerifier: jwtVerifier,
+		Broadcaster: broadcaster,
+
+		RateLimits:      rateLimits,
+		IdempotencyRepo: idempotencyRepo,
+		IdempotencyTTL:  idempotencyTTL,
+	})
+	grpcAddr := ":" + getEnv("GRPC_PORT", "9090")
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		slog.Error("failed to listen for gRPC", "addr", grpcAddr, "error", err)
+		os.Exit(1)
+	}
+	go func() {
+		slog.Info("starting gRPC server", "addr", grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("gRPC server stopped with error", "error", err)
+		}
+	}()
 
 	addr := ":" + port
-	log.Printf("starting server on %s", addr)
+	server := &nethttp.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+	//Потоки событий сами не завершаются, закрываем их при остановке
+	server.RegisterOnShutdown(broadcaster.Close)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("server shutdown failed", "error", err)
+		}
+
+		//Стримы событий не завершаются сами, поэтому GracefulStop ограничен по времени
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}()
+
+	slog.Info("starting server", "addr", addr)
 
 	//Обработка ошибки
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("server stopped with error: %v", err)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+		slog.Error("server stopped with error", "error", err)
+		os.Exit(1)
 	}
 
 }
+
+// getEnv возвращает значение переменной окружения или значение по умолчанию
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDuration разбирает длительность из переменной окружения или значения по умолчанию
+func getDuration(key, fallback string) (time.Duration, error) {
+	return time.ParseDuration(getEnv(key, fallback))
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
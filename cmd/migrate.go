@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Unitazavr/AvitoPR/internal/migrations"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema with the embedded migrations",
+	}
+
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(func(m *migrations.Migrator) error {
+				return m.Up()
+			})
+		},
+	}
+
+	var (
+		steps int
+		all   bool
+	)
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back the latest migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps <= 0 && !all {
+				return errors.New("--steps must be positive")
+			}
+			if all {
+				steps = 0
+			}
+			return withMigrator(func(m *migrations.Migrator) error {
+				return m.Down(steps)
+			})
+		},
+	}
+	down.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	down.Flags().BoolVar(&all, "all", false, "roll back every migration")
+	down.MarkFlagsMutuallyExclusive("steps", "all")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "List embedded migrations and whether they are applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := migrations.List()
+			if err != nil {
+				return err
+			}
+			return withMigrator(func(m *migrations.Migrator) error {
+				version, dirty, err := m.Version()
+				if err != nil {
+					return err
+				}
+
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS")
+				for _, migration := range list {
+					state := "pending"
+					switch {
+					case migration.Version == version && dirty:
+						state = "dirty"
+					case migration.Version <= version:
+						state = "applied"
+					}
+					fmt.Fprintf(tw, "%d\t%s\t%s\n", migration.Version, migration.Name, state)
+				}
+				return tw.Flush()
+			})
+		},
+	}
+
+	version := &cobra.Command{
+		Use:   "version",
+		Short: "Print the schema version of the database and of this binary",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			latest, err := migrations.Latest()
+			if err != nil {
+				return err
+			}
+			return withMigrator(func(m *migrations.Migrator) error {
+				version, dirty, err := m.Version()
+				if err != nil {
+					return err
+				}
+
+				out := cmd.OutOrStdout()
+				fmt.Fprintf(out, "database: %d", version)
+				if dirty {
+					fmt.Fprint(out, " (dirty)")
+				}
+				fmt.Fprintf(out, "\nbinary:   %d\n", latest)
+				return nil
+			})
+		},
+	}
+
+	force := &cobra.Command{
+		Use:   "force VERSION",
+		Short: "Record VERSION as applied and clear the dirty flag without running migrations",
+		Long: `Record VERSION as applied and clear the dirty flag without running migrations.
+
+Use it after fixing a migration that failed halfway: force the last version
+that is fully applied, then run migrate up again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.Atoi(args[0])
+			if err != nil || version < 0 {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+			return withMigrator(func(m *migrations.Migrator) error {
+				return m.Force(version)
+			})
+		},
+	}
+
+	cmd.AddCommand(up, down, status, version, force)
+	return cmd
+}
+
+// migrateUp применяет встроенные миграции перед запуском сервера
+func migrateUp(connConfig *pgx.ConnConfig) error {
+	m, err := migrations.New(connConfig)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Up()
+}
+
+// withMigrator подключается к POSTGRES_DSN на время fn
+func withMigrator(fn func(m *migrations.Migrator) error) error {
+	connConfig, err := migrateConnConfig()
+	if err != nil {
+		return err
+	}
+	m, err := migrations.New(connConfig)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return fn(m)
+}
+
+func migrateConnConfig() (*pgx.ConnConfig, error) {
+	dsn, err := postgresDSN()
+	if err != nil {
+		return nil, err
+	}
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid POSTGRES_DSN: %w", err)
+	}
+	return connConfig, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// client - минимальный клиент HTTP API
+type client struct {
+	baseURL *url.URL
+	token   string
+	http    *http.Client
+}
+
+func newClient(cfg config, timeout time.Duration) (*client, error) {
+	if cfg.Server == "" {
+		return nil, errors.New("server is not set: use --server, PRCTL_SERVER or the config file")
+	}
+	baseURL, err := url.Parse(strings.TrimRight(cfg.Server, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q", cfg.Server)
+	}
+
+	return &client{
+		baseURL: baseURL,
+		token:   cfg.Token,
+		http:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// apiError - ошибка API: либо ErrorResponse, либо {"error": "..."} от валидации запроса
+type apiError struct {
+	status  int
+	code    domain.ErrorCode
+	message string
+}
+
+func (e *apiError) Error() string {
+	if e.code != "" {
+		return fmt.Sprintf("%s: %s (HTTP %d)", e.code, e.message, e.status)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", e.message, e.status)
+}
+
+// getJSON выполняет GET и декодирует ответ в out
+func (c *client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, "", nil, out)
+}
+
+// postJSON отправляет body как JSON и декодирует ответ в out
+func (c *client) postJSON(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, nil, "application/json", bytes.NewReader(data), out)
+}
+
+// postRaw отправляет тело как есть с указанным Content-Type
+func (c *client) postRaw(ctx context.Context, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	return c.do(ctx, http.MethodPost, path, query, contentType, body, out)
+}
+
+func (c *client) do(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	u := *c.baseURL
+	u.Path = c.baseURL.Path + path
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		return decodeError(resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func decodeError(status int, data []byte) error {
+	var structured domain.ErrorResponse
+	if json.Unmarshal(data, &structured) == nil && structured.ErrorContent.Code != "" {
+		return &apiError{status: status, code: structured.ErrorContent.Code, message: structured.ErrorContent.Message}
+	}
+
+	var plain struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &plain) == nil && plain.Error != "" {
+		return &apiError{status: status, message: plain.Error}
+	}
+
+	message := strings.TrimSpace(string(data))
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &apiError{status: status, message: message}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+const defaultConfigHint = "$XDG_CONFIG_HOME/prctl/config.yaml"
+
+// config - файл конфигурации prctl
+type config struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+}
+
+// loadConfig читает конфигурацию. Отсутствие файла по умолчанию не ошибка,
+// а явно указанный файл должен существовать.
+func loadConfig(path string) (config, error) {
+	var cfg config
+
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg, nil
+		}
+		path = filepath.Join(dir, "prctl", "config.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := yaml.UnmarshalWithOptions(data, &cfg, yaml.DisallowUnknownField()); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %s", path, yaml.FormatError(err, false, false))
+	}
+	return cfg, nil
+}
+
+// override заменяет значения непустыми
+func (c *config) override(server, token string) {
+	if server != "" {
+		c.Server = server
+	}
+	if token != "" {
+		c.Token = token
+	}
+}
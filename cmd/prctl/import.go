@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Bulk-import data",
+	}
+	cmd.AddCommand(newImportTeamsCmd(opts))
+	return cmd
+}
+
+func newImportTeamsCmd(opts *globalOptions) *cobra.Command {
+	var (
+		format string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "teams FILE",
+		Short: "Import teams and users from a CSV or YAML file",
+		Long: `Import teams and users from a CSV or YAML file.
+
+The format is taken from --format or the file extension (.csv, .yaml, .yml).
+Use --dry-run to print the changes without applying them.`,
+		Example: `  prctl import teams people.csv --dry-run
+  prctl import teams people.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			if format == "" {
+				switch strings.ToLower(filepath.Ext(args[0])) {
+				case ".csv":
+					format = "csv"
+				case ".yaml", ".yml":
+					format = "yaml"
+				default:
+					return fmt.Errorf("cannot detect format of %s: use --format csv or --format yaml", args[0])
+				}
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			query := url.Values{
+				"format":  {format},
+				"dry_run": {strconv.FormatBool(dryRun)},
+			}
+			var resp struct {
+				Result domain.ImportResult `json:"result"`
+			}
+			if err := c.postRaw(cmd.Context(), "/import/teams", query, "text/"+format, f, &resp); err != nil {
+				return err
+			}
+			result := resp.Result
+
+			return p.print(result, func(t *tableWriter) {
+				if result.DryRun {
+					t.row("Dry run, nothing was changed.")
+				}
+				t.row("CHANGE", "USER ID", "USERNAME", "TEAM", "DETAILS")
+				for _, team := range result.TeamsCreated {
+					t.row("team created", "-", "-", team, "")
+				}
+				for _, ch := range result.Created {
+					t.row("created", ch.UserID, ch.Username, ch.TeamName, "")
+				}
+				for _, ch := range result.Updated {
+					details := ""
+					if ch.OldUsername != "" {
+						details = "renamed from " + ch.OldUsername
+					}
+					t.row("updated", ch.UserID, ch.Username, ch.TeamName, details)
+				}
+				for _, ch := range result.Moved {
+					t.row("moved", ch.UserID, ch.Username, ch.TeamName, "from "+listCell(ch.FromTeams))
+				}
+				for _, ch := range result.Deactivated {
+					t.row("deactivated", ch.UserID, ch.Username, ch.TeamName, "")
+				}
+				t.row(fmt.Sprintf("unchanged: %d", result.Unchanged))
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "file format: csv or yaml (default from the file extension)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only show what would change")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{"csv", "yaml"}, cobra.ShellCompDirectiveNoFileComp,
+	))
+	return cmd
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func TestImportTeams(t *testing.T) {
+	csv := "team,user_id,username,is_active\nbackend,u1,Alice,true\n"
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := domain.ImportResult{
+		TeamsCreated: []string{"backend"},
+		Created:      []domain.ImportChange{{UserID: "u1", Username: "Alice", TeamName: "backend"}},
+		Updated:      []domain.ImportChange{{UserID: "u2", Username: "Bob", TeamName: "backend", OldUsername: "Robert"}},
+		Moved:        []domain.ImportChange{{UserID: "u3", Username: "Eve", TeamName: "backend", FromTeams: []string{"frontend"}}},
+		Deactivated:  []domain.ImportChange{{UserID: "u4", Username: "Mallory", TeamName: "backend"}},
+		Unchanged:    2,
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		result.DryRun = dryRun
+		server, calls := fakeAPI(t, http.StatusOK, map[string]any{"result": result})
+
+		args := []string{"import", "teams", path}
+		if dryRun {
+			args = append(args, "--dry-run")
+		}
+		out, err := runPrctl(t, server, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		call := expectCall(t, calls, http.MethodPost, "/import/teams")
+		if got := call.query.Get("format"); got != "csv" {
+			t.Errorf("format = %q, want csv", got)
+		}
+		if got, want := call.query.Get("dry_run"), map[bool]string{true: "true", false: "false"}[dryRun]; got != want {
+			t.Errorf("dry_run = %q, want %q", got, want)
+		}
+		if call.contentType != "text/csv" || string(call.body) != csv {
+			t.Errorf("request = %s %q, want the file as text/csv", call.contentType, call.body)
+		}
+
+		for _, want := range []string{
+			"team created - - backend",
+			"created u1 Alice backend",
+			"updated u2 Bob backend renamed from Robert",
+			"moved u3 Eve backend from frontend",
+			"deactivated u4 Mallory backend",
+			"unchanged: 2",
+		} {
+			if !hasRow(out, want) {
+				t.Errorf("dry run %v: output has no row %q:\n%s", dryRun, want, out)
+			}
+		}
+		if got := strings.Contains(out, "Dry run, nothing was changed."); got != dryRun {
+			t.Errorf("dry run %v: dry-run notice printed = %v:\n%s", dryRun, got, out)
+		}
+	}
+}
+
+func TestImportTeamsJSONOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.yaml")
+	if err := os.WriteFile(path, []byte("[]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result := domain.ImportResult{
+		DryRun:       true,
+		TeamsCreated: []string{"backend"},
+		Created:      []domain.ImportChange{{UserID: "u1", Username: "Alice", TeamName: "backend"}},
+		Updated:      []domain.ImportChange{},
+		Moved:        []domain.ImportChange{},
+		Deactivated:  []domain.ImportChange{},
+	}
+	server, calls := fakeAPI(t, http.StatusOK, map[string]any{"result": result})
+
+	out, err := runPrctl(t, server, "-o", "json", "import", "teams", path, "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := expectCall(t, calls, http.MethodPost, "/import/teams")
+	if got := call.query.Get("format"); got != "yaml" {
+		t.Errorf("format = %q, want yaml", got)
+	}
+
+	var got domain.ImportResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not an import result: %v\n%s", err, out)
+	}
+	if !got.DryRun || len(got.Created) != 1 || got.Created[0].UserID != "u1" || got.Updated == nil {
+		t.Errorf("output = %+v, want the result from the API", got)
+	}
+}
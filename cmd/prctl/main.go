@@ -0,0 +1,85 @@
+// prctl - административная утилита для HTTP API AvitoPR
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Глобальные флаги; пустые значения берутся из окружения и файла конфигурации
+type globalOptions struct {
+	configPath string
+	server     string
+	token      string
+	output     string
+	timeout    time.Duration
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+
+	root := &cobra.Command{
+		Use:   "prctl",
+		Short: "Administer AvitoPR teams, users and pull requests over the HTTP API",
+		Long: `prctl talks to the AvitoPR HTTP API.
+
+The server URL and token come from flags, then PRCTL_SERVER and PRCTL_TOKEN,
+then the config file (` + defaultConfigHint + `):
+
+  server: http://localhost:8080
+  token: <admin token>`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", "", "config file (default "+defaultConfigHint+")")
+	flags.StringVar(&opts.server, "server", "", "API base URL, e.g. http://localhost:8080")
+	flags.StringVar(&opts.token, "token", "", "API bearer token")
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "output format: table or json")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "request timeout")
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp,
+	))
+
+	root.AddCommand(
+		newTeamCmd(opts),
+		newUserCmd(opts),
+		newPRCmd(opts),
+		newStatsCmd(opts),
+		newImportCmd(opts),
+	)
+
+	return root
+}
+
+// setup собирает клиент и форматтер вывода из глобальных флагов
+func (o *globalOptions) setup(cmd *cobra.Command) (*client, *printer, error) {
+	p, err := newPrinter(cmd.OutOrStdout(), o.output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := loadConfig(o.configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.override(os.Getenv("PRCTL_SERVER"), os.Getenv("PRCTL_TOKEN"))
+	cfg.override(o.server, o.token)
+
+	c, err := newClient(cfg, o.timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, p, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// apiCall - запрос, который получил fake API
+type apiCall struct {
+	method      string
+	path        string
+	query       url.Values
+	contentType string
+	auth        string
+	body        []byte
+}
+
+// decodeBody разбирает JSON-тело запроса
+func (c apiCall) decodeBody(t *testing.T) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal(c.body, &body); err != nil {
+		t.Fatalf("request body %s: %v", c.body, err)
+	}
+	return body
+}
+
+// fakeAPI отвечает на один запрос статусом status и response в JSON
+func fakeAPI(t *testing.T, status int, response any) (*httptest.Server, <-chan apiCall) {
+	t.Helper()
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := make(chan apiCall, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- apiCall{
+			method:      r.Method,
+			path:        r.URL.Path,
+			query:       r.URL.Query(),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			body:        body,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+// runPrctl выполняет prctl против server и возвращает вывод
+func runPrctl(t *testing.T, server *httptest.Server, args ...string) (string, error) {
+	t.Helper()
+	// Без файла конфигурации и переменных окружения пользователя
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PRCTL_SERVER", "")
+	t.Setenv("PRCTL_TOKEN", "")
+
+	var out bytes.Buffer
+	root := newRootCmd()
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs(append([]string{"--server", server.URL, "--token", "admin-token"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+// expectCall проверяет метод, путь и токен запроса
+func expectCall(t *testing.T, calls <-chan apiCall, method, path string) apiCall {
+	t.Helper()
+	select {
+	case call := <-calls:
+		if call.method != method || call.path != path {
+			t.Fatalf("request = %s %s, want %s %s", call.method, call.path, method, path)
+		}
+		if call.auth != "Bearer admin-token" {
+			t.Errorf("Authorization = %q, want Bearer admin-token", call.auth)
+		}
+		return call
+	default:
+		t.Fatalf("no request to %s %s", method, path)
+		return apiCall{}
+	}
+}
+
+// hasRow - есть ли в таблице строка с такими ячейками (пробелы выравнивания не важны)
+func hasRow(out, row string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Join(strings.Fields(line), " ") == row {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAPIErrorIsReported(t *testing.T) {
+	server, calls := fakeAPI(t, http.StatusNotFound, map[string]any{
+		"error": map[string]string{"code": "NOT_FOUND", "message": "team not found"},
+	})
+
+	_, err := runPrctl(t, server, "team", "get", "missing")
+	expectCall(t, calls, http.MethodGet, "/team/get")
+	if err == nil || err.Error() != "NOT_FOUND: team not found (HTTP 404)" {
+		t.Errorf("error = %v, want NOT_FOUND: team not found (HTTP 404)", err)
+	}
+}
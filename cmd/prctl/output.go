@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer выводит ответ API таблицей или JSON
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != outputTable && format != outputJSON {
+		return nil, fmt.Errorf("unknown output format %q: use table or json", format)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+// print выводит v как JSON или вызывает table для табличного вывода
+func (p *printer) print(v any, table func(t *tableWriter)) error {
+	if p.format == outputJSON {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	t := &tableWriter{tw: tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)}
+	table(t)
+	return t.tw.Flush()
+}
+
+// tableWriter - таблица с выравниванием колонок
+type tableWriter struct {
+	tw *tabwriter.Writer
+}
+
+func (t *tableWriter) row(cells ...string) {
+	fmt.Fprintln(t.tw, strings.Join(cells, "\t"))
+}
+
+// section отделяет пустой строкой следующую таблицу
+func (t *tableWriter) section(title string) {
+	fmt.Fprintf(t.tw, "\n%s\n", title)
+}
+
+func boolCell(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+func listCell(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ",")
+}
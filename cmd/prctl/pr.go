@@ -0,0 +1,130 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func newPRCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Create, merge and reassign pull requests",
+	}
+	cmd.AddCommand(newPRCreateCmd(opts), newPRMergeCmd(opts), newPRReassignCmd(opts))
+	return cmd
+}
+
+func newPRCreateCmd(opts *globalOptions) *cobra.Command {
+	var name, author string
+
+	cmd := &cobra.Command{
+		Use:     "create PR_ID --name NAME --author USER_ID",
+		Short:   "Create a pull request and assign reviewers",
+		Example: `  prctl pr create pr-1001 --name "Add search" --author u1`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]string{
+				"pull_request_id":   args[0],
+				"pull_request_name": name,
+				"author_id":         author,
+			}
+			var resp struct {
+				PR domain.PullRequest `json:"pr"`
+			}
+			if err := c.postJSON(cmd.Context(), "/pullRequest/create", body, &resp); err != nil {
+				return err
+			}
+			return printPR(p, resp, resp.PR, "")
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "pull request title")
+	cmd.Flags().StringVar(&author, "author", "", "author user ID")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("author")
+	return cmd
+}
+
+func newPRMergeCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge PR_ID",
+		Short: "Mark a pull request as merged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			var resp struct {
+				PR domain.PullRequest `json:"pr"`
+			}
+			body := map[string]string{"pull_request_id": args[0]}
+			if err := c.postJSON(cmd.Context(), "/pullRequest/merge", body, &resp); err != nil {
+				return err
+			}
+			return printPR(p, resp, resp.PR, "")
+		},
+	}
+}
+
+func newPRReassignCmd(opts *globalOptions) *cobra.Command {
+	var oldReviewer, reason string
+
+	cmd := &cobra.Command{
+		Use:     "reassign PR_ID --old-reviewer USER_ID",
+		Short:   "Replace a reviewer with another member of their team",
+		Example: `  prctl pr reassign pr-1001 --old-reviewer u2 --reason "on vacation"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]string{
+				"pull_request_id": args[0],
+				"old_reviewer_id": oldReviewer,
+				"reason":          reason,
+			}
+			var resp struct {
+				PR         domain.PullRequest `json:"pr"`
+				ReplacedBy string             `json:"replaced_by"`
+			}
+			if err := c.postJSON(cmd.Context(), "/pullRequest/reassign", body, &resp); err != nil {
+				return err
+			}
+			return printPR(p, resp, resp.PR, resp.ReplacedBy)
+		},
+	}
+
+	cmd.Flags().StringVar(&oldReviewer, "old-reviewer", "", "reviewer to replace")
+	cmd.Flags().StringVar(&reason, "reason", "", "reason recorded in the assignment history")
+	_ = cmd.MarkFlagRequired("old-reviewer")
+	return cmd
+}
+
+// printPR выводит PR; в JSON - ответ API целиком
+func printPR(p *printer, resp any, pr domain.PullRequest, replacedBy string) error {
+	return p.print(resp, func(t *tableWriter) {
+		t.row("PR", pr.PullRequestID)
+		t.row("NAME", pr.PullRequestName)
+		t.row("AUTHOR", pr.AuthorID)
+		t.row("TEAM", pr.TeamName)
+		t.row("STATUS", string(pr.Status))
+		t.row("REVIEWERS", listCell(pr.AssignedReviewers))
+		if replacedBy != "" {
+			t.row("REPLACED BY", replacedBy)
+		}
+		if pr.MergedAt != nil {
+			t.row("MERGED AT", pr.MergedAt.Format(time.RFC3339))
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func TestPRCommands(t *testing.T) {
+	mergedAt := time.Date(2026, time.April, 27, 10, 0, 0, 0, time.UTC)
+	open := domain.PullRequest{
+		PullRequestID:     "pr-1001",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		TeamName:          "backend",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+	}
+	merged := open
+	merged.Status = domain.PRStatusMerged
+	merged.MergedAt = &mergedAt
+	reassigned := open
+	reassigned.AssignedReviewers = []string{"u4", "u3"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		status   int
+		response map[string]any
+		path     string
+		body     map[string]any
+		rows     []string
+	}{
+		{
+			name:     "create",
+			args:     []string{"pr", "create", "pr-1001", "--name", "Add search", "--author", "u1"},
+			status:   http.StatusCreated,
+			response: map[string]any{"pr": open},
+			path:     "/pullRequest/create",
+			body:     map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1"},
+			rows:     []string{"PR pr-1001", "NAME Add search", "STATUS OPEN", "REVIEWERS u2,u3"},
+		},
+		{
+			name:     "merge",
+			args:     []string{"pr", "merge", "pr-1001"},
+			status:   http.StatusOK,
+			response: map[string]any{"pr": merged},
+			path:     "/pullRequest/merge",
+			body:     map[string]any{"pull_request_id": "pr-1001"},
+			rows:     []string{"STATUS MERGED", "MERGED AT 2026-04-27T10:00:00Z"},
+		},
+		{
+			name:     "reassign",
+			args:     []string{"pr", "reassign", "pr-1001", "--old-reviewer", "u2", "--reason", "on vacation"},
+			status:   http.StatusOK,
+			response: map[string]any{"pr": reassigned, "replaced_by": "u4"},
+			path:     "/pullRequest/reassign",
+			body:     map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": "u2", "reason": "on vacation"},
+			rows:     []string{"REVIEWERS u4,u3", "REPLACED BY u4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := fakeAPI(t, tt.status, tt.response)
+
+			out, err := runPrctl(t, server, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			call := expectCall(t, calls, http.MethodPost, tt.path)
+			if got := call.decodeBody(t); !reflect.DeepEqual(got, tt.body) {
+				t.Errorf("body = %v, want %v", got, tt.body)
+			}
+			for _, row := range tt.rows {
+				if !hasRow(out, row) {
+					t.Errorf("output has no row %q:\n%s", row, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPRReassignJSONOutput(t *testing.T) {
+	pr := domain.PullRequest{PullRequestID: "pr-1001", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u4"}}
+	server, calls := fakeAPI(t, http.StatusOK, map[string]any{"pr": pr, "replaced_by": "u4"})
+
+	out, err := runPrctl(t, server, "-o", "json", "pr", "reassign", "pr-1001", "--old-reviewer", "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectCall(t, calls, http.MethodPost, "/pullRequest/reassign")
+
+	var got struct {
+		PR         domain.PullRequest `json:"pr"`
+		ReplacedBy string             `json:"replaced_by"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not the API response: %v\n%s", err, out)
+	}
+	if got.PR.PullRequestID != "pr-1001" || got.ReplacedBy != "u4" {
+		t.Errorf("output = %+v, want the API response", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show review statistics",
+	}
+	cmd.AddCommand(newStatsTurnaroundCmd(opts))
+	return cmd
+}
+
+func newStatsTurnaroundCmd(opts *globalOptions) *cobra.Command {
+	var from, to, team string
+
+	cmd := &cobra.Command{
+		Use:     "turnaround",
+		Short:   "Show time-to-merge and time-to-verdict percentiles",
+		Example: `  prctl stats turnaround --from 2026-01-01 --to 2026-01-31 --team backend`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if from != "" {
+				query.Set("from", from)
+			}
+			if to != "" {
+				query.Set("to", to)
+			}
+			if team != "" {
+				query.Set("team_name", team)
+			}
+
+			var stats domain.TurnaroundStats
+			if err := c.getJSON(cmd.Context(), "/stats/turnaround", query, &stats); err != nil {
+				return err
+			}
+
+			return p.print(stats, func(t *tableWriter) {
+				t.row("PERIOD", stats.From+" .. "+stats.To)
+				t.section("TEAM\tPRS\tP50\tP90\tP99")
+				for _, s := range stats.Teams {
+					t.row(append([]string{s.TeamName}, percentileCells(s.TimeToMerge)...)...)
+				}
+				t.section("AUTHOR\tPRS\tP50\tP90\tP99")
+				for _, s := range stats.Authors {
+					t.row(append([]string{s.AuthorID}, percentileCells(s.TimeToMerge)...)...)
+				}
+				t.section("REVIEWER\tVERDICTS\tP50\tP90\tP99")
+				for _, s := range stats.Reviewers {
+					t.row(append([]string{s.ReviewerID}, percentileCells(s.TimeToVerdict)...)...)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "first day, YYYY-MM-DD (default 30 days ago)")
+	cmd.Flags().StringVar(&to, "to", "", "last day, YYYY-MM-DD (default today)")
+	cmd.Flags().StringVar(&team, "team", "", "limit to one team")
+	return cmd
+}
+
+func percentileCells(p domain.Percentiles) []string {
+	return []string{
+		strconv.Itoa(p.Count),
+		formatSeconds(p.P50),
+		formatSeconds(p.P90),
+		formatSeconds(p.P99),
+	}
+}
+
+// formatSeconds выводит длительность с точностью до минуты
+func formatSeconds(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Minute)
+	if d == 0 {
+		return fmt.Sprintf("%.0fs", seconds)
+	}
+	return d.String()
+}
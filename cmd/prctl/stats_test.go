@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func TestStatsTurnaround(t *testing.T) {
+	server, calls := fakeAPI(t, http.StatusOK, domain.TurnaroundStats{
+		From:     "2026-04-01",
+		To:       "2026-04-30",
+		TeamName: "backend",
+		Teams: []domain.TeamTurnaround{
+			{TeamName: "backend", TimeToMerge: domain.Percentiles{Count: 4, P50: 3600, P90: 7200, P99: 9000}},
+		},
+		Authors: []domain.AuthorTurnaround{
+			{AuthorID: "u1", TimeToMerge: domain.Percentiles{Count: 1, P50: 20, P90: 20, P99: 20}},
+		},
+		Reviewers: []domain.ReviewerTurnaround{
+			{ReviewerID: "u2", TimeToVerdict: domain.Percentiles{Count: 2, P50: 1800, P90: 5400, P99: 5400}},
+		},
+	})
+
+	out, err := runPrctl(t, server, "stats", "turnaround", "--from", "2026-04-01", "--to", "2026-04-30", "--team", "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := expectCall(t, calls, http.MethodGet, "/stats/turnaround")
+	for name, want := range map[string]string{"from": "2026-04-01", "to": "2026-04-30", "team_name": "backend"} {
+		if got := call.query.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	for _, row := range []string{
+		"PERIOD 2026-04-01 .. 2026-04-30",
+		"backend 4 1h0m0s 2h0m0s 2h30m0s",
+		"u1 1 20s 20s 20s",
+		"u2 2 30m0s 1h30m0s 1h30m0s",
+	} {
+		if !hasRow(out, row) {
+			t.Errorf("output has no row %q:\n%s", row, out)
+		}
+	}
+}
+
+func TestStatsTurnaroundDefaultRange(t *testing.T) {
+	server, calls := fakeAPI(t, http.StatusOK, domain.TurnaroundStats{})
+
+	if _, err := runPrctl(t, server, "stats", "turnaround"); err != nil {
+		t.Fatal(err)
+	}
+	// Пустые параметры не отправляются, диапазон по умолчанию выбирает сервер
+	if call := expectCall(t, calls, http.MethodGet, "/stats/turnaround"); len(call.query) != 0 {
+		t.Errorf("query = %v, want none", call.query)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func newTeamCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "team",
+		Short: "Create and inspect teams",
+	}
+	cmd.AddCommand(newTeamCreateCmd(opts), newTeamGetCmd(opts))
+	return cmd
+}
+
+func newTeamCreateCmd(opts *globalOptions) *cobra.Command {
+	var (
+		members    []string
+		inactive   []string
+		slaMinutes int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create TEAM --member USER_ID=USERNAME...",
+		Short: "Create a team with its members",
+		Example: `  prctl team create backend --member u1=Alice --member u2=Bob
+  prctl team create backend --member u1=Alice --inactive u1 --review-sla 240`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			inactiveSet := make(map[string]bool, len(inactive))
+			for _, id := range inactive {
+				inactiveSet[id] = true
+			}
+
+			body := struct {
+				TeamName         string              `json:"team_name"`
+				Members          []domain.TeamMember `json:"members"`
+				ReviewSLAMinutes *int                `json:"review_sla_minutes,omitempty"`
+			}{TeamName: args[0], Members: []domain.TeamMember{}}
+
+			for _, m := range members {
+				id, name, ok := strings.Cut(m, "=")
+				if !ok || id == "" || name == "" {
+					return fmt.Errorf("invalid --member %q: expected USER_ID=USERNAME", m)
+				}
+				body.Members = append(body.Members, domain.TeamMember{UserID: id, Username: name, IsActive: !inactiveSet[id]})
+				delete(inactiveSet, id)
+			}
+			for id := range inactiveSet {
+				return fmt.Errorf("--inactive %q is not listed in --member", id)
+			}
+			if cmd.Flags().Changed("review-sla") {
+				body.ReviewSLAMinutes = &slaMinutes
+			}
+
+			var resp struct {
+				Team domain.Team `json:"team"`
+			}
+			if err := c.postJSON(cmd.Context(), "/team/add", body, &resp); err != nil {
+				return err
+			}
+			return printTeam(p, resp.Team)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&members, "member", nil, "team member as USER_ID=USERNAME, repeatable")
+	cmd.Flags().StringSliceVar(&inactive, "inactive", nil, "user IDs to create as inactive")
+	cmd.Flags().IntVar(&slaMinutes, "review-sla", 0, "review SLA in minutes")
+	return cmd
+}
+
+func newTeamGetCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get TEAM",
+		Short: "Show a team and its members",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			var team domain.Team
+			if err := c.getJSON(cmd.Context(), "/team/get", url.Values{"team_name": {args[0]}}, &team); err != nil {
+				return err
+			}
+			return printTeam(p, team)
+		},
+	}
+}
+
+func printTeam(p *printer, team domain.Team) error {
+	return p.print(team, func(t *tableWriter) {
+		sla := "-"
+		if team.ReviewSLAMinutes != nil {
+			sla = fmt.Sprintf("%dm", *team.ReviewSLAMinutes)
+		}
+		t.row("TEAM", team.TeamName)
+		t.row("REVIEW SLA", sla)
+		t.section("USER ID\tUSERNAME\tACTIVE")
+		for _, m := range team.Members {
+			t.row(m.UserID, m.Username, boolCell(m.IsActive))
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func TestTeamCreate(t *testing.T) {
+	sla := 240
+	server, calls := fakeAPI(t, http.StatusCreated, map[string]any{"team": domain.Team{
+		TeamName: "backend",
+		Members: []domain.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: false},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		},
+		ReviewSLAMinutes: &sla,
+	}})
+
+	out, err := runPrctl(t, server, "team", "create", "backend",
+		"--member", "u1=Alice", "--member", "u2=Bob", "--inactive", "u1", "--review-sla", "240")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := expectCall(t, calls, http.MethodPost, "/team/add")
+	want := map[string]any{
+		"team_name": "backend",
+		"members": []any{
+			map[string]any{"user_id": "u1", "username": "Alice", "is_active": false},
+			map[string]any{"user_id": "u2", "username": "Bob", "is_active": true},
+		},
+		"review_sla_minutes": float64(240),
+	}
+	if got := call.decodeBody(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+
+	for _, row := range []string{"TEAM backend", "REVIEW SLA 240m", "u1 Alice no", "u2 Bob yes"} {
+		if !hasRow(out, row) {
+			t.Errorf("output has no row %q:\n%s", row, out)
+		}
+	}
+}
+
+func TestTeamCreateRejectsUnknownInactive(t *testing.T) {
+	server, calls := fakeAPI(t, http.StatusCreated, nil)
+
+	if _, err := runPrctl(t, server, "team", "create", "backend", "--member", "u1=Alice", "--inactive", "u9"); err == nil {
+		t.Fatal("--inactive for a user outside --member accepted")
+	}
+	if len(calls) != 0 {
+		t.Error("request sent for invalid flags")
+	}
+}
+
+func TestTeamGet(t *testing.T) {
+	server, calls := fakeAPI(t, http.StatusOK, domain.Team{
+		TeamName: "backend",
+		Members:  []domain.TeamMember{{UserID: "u1", Username: "Alice", IsActive: true}},
+	})
+
+	out, err := runPrctl(t, server, "team", "get", "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := expectCall(t, calls, http.MethodGet, "/team/get")
+	if got := call.query.Get("team_name"); got != "backend" {
+		t.Errorf("team_name = %q, want backend", got)
+	}
+	for _, row := range []string{"TEAM backend", "REVIEW SLA -", "u1 Alice yes"} {
+		if !hasRow(out, row) {
+			t.Errorf("output has no row %q:\n%s", row, out)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	cmd.AddCommand(
+		newUserSetActiveCmd(opts, "activate", "Mark a user as active so they can be assigned reviews", true),
+		newUserSetActiveCmd(opts, "deactivate", "Mark a user as inactive so they are no longer assigned reviews", false),
+	)
+	return cmd
+}
+
+func newUserSetActiveCmd(opts *globalOptions, use, short string, active bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USER_ID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, p, err := opts.setup(cmd)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]any{"user_id": args[0], "is_active": active}
+			var resp struct {
+				User domain.User `json:"user"`
+			}
+			if err := c.postJSON(cmd.Context(), "/users/setIsActive", body, &resp); err != nil {
+				return err
+			}
+
+			return p.print(resp.User, func(t *tableWriter) {
+				t.row("USER ID", "USERNAME", "TEAM", "ACTIVE")
+				t.row(resp.User.UserID, resp.User.Username, resp.User.TeamName, boolCell(resp.User.IsActive))
+			})
+		},
+	}
+}
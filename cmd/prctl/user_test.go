@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func TestUserSetActive(t *testing.T) {
+	tests := []struct {
+		command string
+		active  bool
+		row     string
+	}{
+		{command: "activate", active: true, row: "u1 Alice backend yes"},
+		{command: "deactivate", active: false, row: "u1 Alice backend no"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			server, calls := fakeAPI(t, http.StatusOK, map[string]any{"user": domain.User{
+				UserID:   "u1",
+				Username: "Alice",
+				TeamName: "backend",
+				IsActive: tt.active,
+			}})
+
+			out, err := runPrctl(t, server, "user", tt.command, "u1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			call := expectCall(t, calls, http.MethodPost, "/users/setIsActive")
+			want := map[string]any{"user_id": "u1", "is_active": tt.active}
+			if got := call.decodeBody(t); !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %v, want %v", got, want)
+			}
+			if !hasRow(out, tt.row) {
+				t.Errorf("output has no row %q:\n%s", tt.row, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Префикс выдаваемых токенов, по нему их проще искать в логах и секретах
+const tokenPrefix = "prt_"
+
+type identityKey struct{}
+
+// WithIdentity кладёт в контекст того, кто выполняет запрос
+func WithIdentity(ctx context.Context, identity *domain.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext возвращает вызывающего или nil для внутренних вызовов
+func IdentityFromContext(ctx context.Context) *domain.Identity {
+	identity, _ := ctx.Value(identityKey{}).(*domain.Identity)
+	return identity
+}
+
+// GenerateToken создаёт новый случайный токен
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashToken - sha256 токена. Токены случайные и длинные, поэтому
+// медленный хэш вроде bcrypt не нужен, а поиск по хэшу остаётся индексным.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken достаёт токен из заголовка Authorization: Bearer <token>
+func BearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer отдаёт текущий JWKS и считает запросы
+type jwksServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	body    []byte
+	status  int
+	fetches int
+}
+
+func newJWKSServer(t *testing.T, body []byte) *jwksServer {
+	s := &jwksServer{body: body, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+		w.WriteHeader(s.status)
+		w.Write(s.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, body
+}
+
+func (s *jwksServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+// age сдвигает время последней загрузки ключей в прошлое
+func (ks *KeySet) age(d time.Duration) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.fetchedAt = ks.fetchedAt.Add(-d)
+}
+
+func TestKeySetRefreshesOnUnknownKid(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newECKey(t, "new")
+	server := newJWKSServer(t, jwks(t, oldKey))
+
+	keys, err := NewKeySet(context.Background(), server.URL, server.Client(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewJWTVerifier(keys, JWTConfig{})
+	rotated := sign(t, jwt.SigningMethodES256, "new", newKey.private, validClaims())
+
+	// Шлюз перешёл на новый ключ
+	server.set(http.StatusOK, jwks(t, oldKey, newKey))
+
+	// Сразу после загрузки JWKS не перечитывается даже ради неизвестного kid
+	if _, err := verifier.Verify(context.Background(), rotated); err == nil {
+		t.Fatal("token with an unknown kid accepted before refresh")
+	}
+	if got := server.fetchCount(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+
+	keys.age(2 * minKeyRefresh)
+	if _, err := verifier.Verify(context.Background(), rotated); err != nil {
+		t.Fatalf("Verify after refresh: %v", err)
+	}
+	if got := server.fetchCount(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+
+	// Известный kid не вызывает перечитывания
+	if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "old", oldKey.private, validClaims())); err != nil {
+		t.Fatalf("Verify with a known kid: %v", err)
+	}
+	if got := server.fetchCount(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestKeySetKeepsKeysWhenSourceFails(t *testing.T) {
+	key := newRSAKey(t, "rsa")
+	server := newJWKSServer(t, jwks(t, key))
+
+	keys, err := NewKeySet(context.Background(), server.URL, server.Client(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewJWTVerifier(keys, JWTConfig{})
+
+	server.set(http.StatusInternalServerError, nil)
+	keys.age(2 * time.Hour)
+
+	if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key.private, validClaims())); err != nil {
+		t.Fatalf("Verify with stale keys: %v", err)
+	}
+	if got := server.fetchCount(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	ecKey := newECKey(t, "ec")
+
+	tests := []struct {
+		name    string
+		data    string
+		kids    []string
+		wantErr bool
+	}{
+		{name: "rsa and ec", data: string(jwks(t, rsaKey, ecKey)), kids: []string{"rsa", "ec"}},
+		{
+			name: "encryption and unknown keys are skipped",
+			data: `{"keys":[{"kty":"RSA","kid":"enc","use":"enc"},{"kty":"OKP","kid":"ed"},` +
+				string(jwks(t, ecKey))[len(`{"keys":[`):],
+			kids: []string{"ec"},
+		},
+		{name: "no signing keys", data: `{"keys":[{"kty":"oct","kid":"h"}]}`, wantErr: true},
+		{name: "short rsa modulus", data: `{"keys":[{"kty":"RSA","kid":"r","n":"AQAB","e":"AQAB"}]}`, wantErr: true},
+		{
+			name:    "ec point off the curve",
+			data:    `{"keys":[{"kty":"EC","kid":"e","crv":"P-256","x":"` + zeros32 + `","y":"` + zeros32 + `"}]}`,
+			wantErr: true,
+		},
+		{name: "not json", data: `keys`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseJWKS([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseJWKS = %v, want error", keys)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJWKS: %v", err)
+			}
+			if len(keys) != len(tt.kids) {
+				t.Errorf("got %d keys, want %v", len(keys), tt.kids)
+			}
+			for _, kid := range tt.kids {
+				if _, ok := keys[kid]; !ok {
+					t.Errorf("key %q missing", kid)
+				}
+			}
+		})
+	}
+}
+
+// 32 нулевых байта в base64url - координата точки не на кривой
+const zeros32 = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Допустимое расхождение часов с выпускающим токены шлюзом
+const clockSkew = 30 * time.Second
+
+// JWTConfig - проверка и сопоставление claims
+type JWTConfig struct {
+	// Ожидаемые iss и aud, пустые значения не проверяются
+	Issuer   string
+	Audience string
+	// Claim с идентификатором пользователя сервиса
+	UserClaim string
+	// Claim с ролью: строка или массив строк
+	RoleClaim string
+	// Значение роли, дающее права администратора
+	AdminRole string
+}
+
+// JWTVerifier проверяет RS256/ES256 токены по ключам из JWKS
+type JWTVerifier struct {
+	keys   *KeySet
+	config JWTConfig
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(keys *KeySet, config JWTConfig) *JWTVerifier {
+	if config.UserClaim == "" {
+		config.UserClaim = "sub"
+	}
+	if config.RoleClaim == "" {
+		config.RoleClaim = "role"
+	}
+	if config.AdminRole == "" {
+		config.AdminRole = string(domain.RoleAdmin)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTVerifier{
+		keys:   keys,
+		config: config,
+		parser: jwt.NewParser(options...),
+	}
+}
+
+// LooksLikeJWT отличает JWT от непрозрачных токенов API
+func LooksLikeJWT(token string) bool {
+	return !strings.HasPrefix(token, tokenPrefix) && strings.Count(token, ".") == 2
+}
+
+// Verify проверяет подпись и срок действия и сопоставляет claims с пользователем и ролью
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*domain.Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims.GetSubject()
+	userID, _ := claims[v.config.UserClaim].(string)
+
+	role := domain.RoleUser
+	if slices.Contains(claimStrings(claims[v.config.RoleClaim]), v.config.AdminRole) {
+		role = domain.RoleAdmin
+	}
+	if role == domain.RoleUser && userID == "" {
+		return nil, fmt.Errorf("claim %q is required", v.config.UserClaim)
+	}
+	if subject == "" && userID == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	return &domain.Identity{
+		Role:    role,
+		UserID:  userID,
+		Subject: subject,
+	}, nil
+}
+
+// claimStrings приводит claim-строку или массив строк к срезу
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
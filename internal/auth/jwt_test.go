@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testKey - ключ подписи и его kid в JWKS
+type testKey struct {
+	kid     string
+	private crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, private: key}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, private: key}
+}
+
+// jwks собирает JWKS с публичными частями ключей
+func jwks(t *testing.T, keys ...testKey) []byte {
+	t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for _, k := range keys {
+		switch pub := k.private.Public().(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, jsonWebKey{
+				Kty: "RSA", Kid: k.kid, Use: "sig",
+				N: b64(pub.N.Bytes()),
+				E: b64(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			set.Keys = append(set.Keys, jsonWebKey{
+				Kty: "EC", Kid: k.kid, Use: "sig", Crv: "P-256",
+				X: b64(pub.X.FillBytes(make([]byte, 32))),
+				Y: b64(pub.Y.FillBytes(make([]byte, 32))),
+			})
+		}
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fileKeySet - набор ключей из временного JWKS-файла
+func fileKeySet(t *testing.T, keys ...testKey) *KeySet {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(t, keys...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeySet(context.Background(), path, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "u1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	ecKey := newECKey(t, "ec")
+	foreign := newRSAKey(t, "rsa")
+	verifier := NewJWTVerifier(fileKeySet(t, rsaKey, ecKey), JWTConfig{})
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{
+			name:  "RS256",
+			token: sign(t, jwt.SigningMethodRS256, "rsa", rsaKey.private, validClaims()),
+			ok:    true,
+		},
+		{
+			name:  "ES256",
+			token: sign(t, jwt.SigningMethodES256, "ec", ecKey.private, validClaims()),
+			ok:    true,
+		},
+		{
+			name:  "RS256 signed by another key",
+			token: sign(t, jwt.SigningMethodRS256, "rsa", foreign.private, validClaims()),
+		},
+		{
+			name:  "RS256 with kid of an EC key",
+			token: sign(t, jwt.SigningMethodRS256, "ec", rsaKey.private, validClaims()),
+		},
+		{
+			name:  "ES256 with kid of an RSA key",
+			token: sign(t, jwt.SigningMethodES256, "rsa", ecKey.private, validClaims()),
+		},
+		{
+			name:  "unknown kid",
+			token: sign(t, jwt.SigningMethodRS256, "other", rsaKey.private, validClaims()),
+		},
+		{
+			name:  "RS512 is not accepted",
+			token: sign(t, jwt.SigningMethodRS512, "rsa", rsaKey.private, validClaims()),
+		},
+		{
+			name:  "HS256 is not accepted",
+			token: sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims()),
+		},
+		{
+			name:  "alg none is not accepted",
+			token: sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if identity.UserID != "u1" || identity.Role != domain.RoleUser {
+					t.Errorf("identity = %+v, want user u1", identity)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Verify accepted the token: %+v", identity)
+			}
+		})
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	key := newRSAKey(t, "rsa")
+	keys := fileKeySet(t, key)
+	verifier := NewJWTVerifier(keys, JWTConfig{Issuer: "gateway", Audience: "avito-pr"})
+
+	now := time.Now()
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "u1",
+			"iss": "gateway",
+			"aud": "avito-pr",
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(c, name)
+				continue
+			}
+			c[name] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		ok     bool
+	}{
+		{name: "valid", claims: claims(nil), ok: true},
+		{name: "audience in a list", claims: claims(jwt.MapClaims{"aud": []string{"other", "avito-pr"}}), ok: true},
+		{name: "expired within clock skew", claims: claims(jwt.MapClaims{"exp": now.Add(-clockSkew / 2).Unix()}), ok: true},
+		{name: "no exp", claims: claims(jwt.MapClaims{"exp": nil})},
+		{name: "expired", claims: claims(jwt.MapClaims{"exp": now.Add(-2 * clockSkew).Unix()})},
+		{name: "not yet valid", claims: claims(jwt.MapClaims{"nbf": now.Add(2 * clockSkew).Unix()})},
+		{name: "wrong issuer", claims: claims(jwt.MapClaims{"iss": "someone"})},
+		{name: "no issuer", claims: claims(jwt.MapClaims{"iss": nil})},
+		{name: "wrong audience", claims: claims(jwt.MapClaims{"aud": "other"})},
+		{name: "no audience", claims: claims(jwt.MapClaims{"aud": nil})},
+		{name: "user without user claim", claims: claims(jwt.MapClaims{"sub": nil})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key.private, tt.claims))
+			if tt.ok && err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("Verify accepted the token")
+			}
+		})
+	}
+}
+
+func TestVerifyMapsClaims(t *testing.T) {
+	key := newRSAKey(t, "rsa")
+	verifier := NewJWTVerifier(fileKeySet(t, key), JWTConfig{
+		UserClaim: "user_id",
+		RoleClaim: "roles",
+		AdminRole: "pr-admin",
+	})
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   domain.Identity
+	}{
+		{
+			name:   "user",
+			claims: jwt.MapClaims{"sub": "alice", "user_id": "u1", "roles": "viewer"},
+			want:   domain.Identity{Role: domain.RoleUser, UserID: "u1", Subject: "alice"},
+		},
+		{
+			name:   "admin from a role list",
+			claims: jwt.MapClaims{"sub": "ci", "roles": []string{"viewer", "pr-admin"}},
+			want:   domain.Identity{Role: domain.RoleAdmin, Subject: "ci"},
+		},
+		{
+			name:   "admin from space-separated roles",
+			claims: jwt.MapClaims{"sub": "ci", "roles": "viewer pr-admin"},
+			want:   domain.Identity{Role: domain.RoleAdmin, Subject: "ci"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+			identity, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key.private, tt.claims))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if *identity != tt.want {
+				t.Errorf("identity = %+v, want %+v", *identity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package businesstime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Значения по умолчанию для незаполненных полей расписания
+const (
+	DefaultTimezone  = "UTC"
+	DefaultWorkStart = "09:00"
+	DefaultWorkEnd   = "18:00"
+)
+
+// DefaultWorkDays - понедельник-пятница, дни недели по ISO (1 - понедельник)
+var DefaultWorkDays = []int{1, 2, 3, 4, 5}
+
+// Calendar считает рабочее время: рабочие часы в рабочие дни недели,
+// кроме праздников, в часовом поясе расписания. Нулевой Calendar
+// считает рабочим всё время.
+type Calendar struct {
+	location *time.Location
+	// Начало и конец рабочего дня в минутах от полуночи
+	workStart int
+	workEnd   int
+	workDays  [7]bool // индекс - time.Weekday
+	holidays  map[string]struct{}
+	always    bool
+}
+
+// Always - календарь без выходных и нерабочих часов
+func Always() *Calendar {
+	return &Calendar{always: true}
+}
+
+// New собирает календарь из расписания команды, расписания пользователя
+// (его поля важнее командных) и праздников команды. Если расписания не заданы,
+// возвращается Always.
+func New(team, user *domain.WorkSchedule, holidays []domain.Holiday) (*Calendar, error) {
+	if team == nil && user == nil {
+		return Always(), nil
+	}
+
+	schedule := Merge(team, user)
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", schedule.Timezone, err)
+	}
+	workStart, err := ParseClock(schedule.WorkStart)
+	if err != nil {
+		return nil, err
+	}
+	workEnd, err := ParseClock(schedule.WorkEnd)
+	if err != nil {
+		return nil, err
+	}
+	if workStart >= workEnd {
+		return nil, fmt.Errorf("work_start %s must be before work_end %s", schedule.WorkStart, schedule.WorkEnd)
+	}
+
+	c := &Calendar{
+		location:  location,
+		workStart: workStart,
+		workEnd:   workEnd,
+		holidays:  make(map[string]struct{}, len(holidays)),
+	}
+	for _, day := range schedule.WorkDays {
+		if day < 1 || day > 7 {
+			return nil, fmt.Errorf("invalid work day %d, expected 1 (Monday) to 7 (Sunday)", day)
+		}
+		c.workDays[day%7] = true
+	}
+	for _, holiday := range holidays {
+		c.holidays[holiday.Date] = struct{}{}
+	}
+	return c, nil
+}
+
+// Merge дополняет расписание пользователя полями команды и значениями по умолчанию
+func Merge(team, user *domain.WorkSchedule) domain.WorkSchedule {
+	schedule := domain.WorkSchedule{
+		Timezone:  DefaultTimezone,
+		WorkStart: DefaultWorkStart,
+		WorkEnd:   DefaultWorkEnd,
+		WorkDays:  DefaultWorkDays,
+	}
+	for _, s := range []*domain.WorkSchedule{team, user} {
+		if s == nil {
+			continue
+		}
+		if s.Timezone != "" {
+			schedule.Timezone = s.Timezone
+		}
+		if s.WorkStart != "" {
+			schedule.WorkStart = s.WorkStart
+		}
+		if s.WorkEnd != "" {
+			schedule.WorkEnd = s.WorkEnd
+		}
+		if len(s.WorkDays) > 0 {
+			schedule.WorkDays = s.WorkDays
+		}
+	}
+	return schedule
+}
+
+// ParseClock разбирает время "HH:MM" в минуты от полуночи, "24:00" - конец суток
+func ParseClock(value string) (int, error) {
+	var hours, minutes int
+	if len(value) != 5 || value[2] != ':' {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	if _, err := fmt.Sscanf(value, "%02d:%02d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	if minutes < 0 || minutes > 59 || hours < 0 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// IsWorkingTime - попадает ли момент t в рабочие часы
+func (c *Calendar) IsWorkingTime(t time.Time) bool {
+	if c.always {
+		return true
+	}
+	start, end, ok := c.workingHours(t.In(c.location))
+	return ok && !t.Before(start) && t.Before(end)
+}
+
+// Elapsed возвращает рабочее время между from и to
+func (c *Calendar) Elapsed(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if c.always {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+	day := from.In(c.location)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.location)
+	for day.Before(to) {
+		if start, end, ok := c.workingHours(day); ok {
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// workingHours - начало и конец рабочего дня, в который попадает t, если день рабочий
+func (c *Calendar) workingHours(t time.Time) (start, end time.Time, ok bool) {
+	if !c.workDays[t.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	if _, holiday := c.holidays[t.Format(time.DateOnly)]; holiday {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+	// Часы и минуты через time.Date, чтобы переход на летнее время не сдвигал границы
+	start = time.Date(t.Year(), t.Month(), t.Day(), c.workStart/60, c.workStart%60, 0, 0, c.location)
+	if c.workEnd == 24*60 {
+		end = midnight.AddDate(0, 0, 1)
+	} else {
+		end = time.Date(t.Year(), t.Month(), t.Day(), c.workEnd/60, c.workEnd%60, 0, 0, c.location)
+	}
+	return start, end, true
+}
@@ -0,0 +1,162 @@
+package businesstime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+func mustCalendar(t *testing.T, team, user *domain.WorkSchedule, holidays ...string) *Calendar {
+	t.Helper()
+	var days []domain.Holiday
+	for _, date := range holidays {
+		days = append(days, domain.Holiday{Date: date})
+	}
+	c, err := New(team, user, days)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+var everyDay = []int{1, 2, 3, 4, 5, 6, 7}
+
+func TestCalendarElapsed(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	berlin := mustLocation(t, "Europe/Berlin")
+	msk := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, moscow)
+	}
+	cet := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, berlin)
+	}
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	// Пн-Пт 10:00-19:00 по Москве, 1 мая (пятница) - праздник
+	office := mustCalendar(t, &domain.WorkSchedule{
+		Timezone:  "Europe/Moscow",
+		WorkStart: "10:00",
+		WorkEnd:   "19:00",
+	}, nil, "2026-05-01")
+	// Ежедневно 20:00-24:00 по UTC
+	evenings := mustCalendar(t, &domain.WorkSchedule{WorkStart: "20:00", WorkEnd: "24:00", WorkDays: everyDay}, nil)
+	// Круглосуточно по Берлину: сутки перехода на летнее время короче на час
+	berlinAllDay := mustCalendar(t, &domain.WorkSchedule{
+		Timezone:  "Europe/Berlin",
+		WorkStart: "00:00",
+		WorkEnd:   "24:00",
+		WorkDays:  everyDay,
+	}, nil)
+	berlinOffice := mustCalendar(t, &domain.WorkSchedule{Timezone: "Europe/Berlin"}, nil)
+
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a working day", office, msk(4, 27, 11, 0), msk(4, 27, 13, 30), 2*time.Hour + 30*time.Minute},
+		{"clipped to working hours", office, msk(4, 27, 8, 0), msk(4, 27, 20, 0), 9 * time.Hour},
+		{"overnight", office, msk(4, 27, 18, 0), msk(4, 28, 11, 0), 2 * time.Hour},
+		{"over a weekend", office, msk(4, 24, 18, 0), msk(4, 27, 11, 0), 2 * time.Hour},
+		{"over a holiday and a weekend", office, msk(4, 30, 18, 0), msk(5, 4, 11, 0), 2 * time.Hour},
+		{"a whole week with a holiday", office, msk(4, 27, 10, 0), msk(5, 4, 10, 0), 4 * 9 * time.Hour},
+		{"only non-working time", office, msk(4, 25, 9, 0), msk(4, 26, 23, 0), 0},
+		{"instants in another zone", office, utc(4, 27, 7), utc(4, 27, 9), 2 * time.Hour},
+		{"to before from", office, msk(4, 27, 13, 0), msk(4, 27, 11, 0), 0},
+		{"work until midnight", evenings, utc(4, 27, 23), utc(4, 28, 21), 2 * time.Hour},
+		{"ends exactly at midnight", evenings, utc(4, 27, 21), utc(4, 28, 0), 3 * time.Hour},
+		{"spring DST day is 23 hours", berlinAllDay, cet(3, 28, 12), cet(3, 29, 12), 23 * time.Hour},
+		{"autumn DST day is 25 hours", berlinAllDay, cet(10, 24, 12), cet(10, 25, 12), 25 * time.Hour},
+		{"working hours follow local time over DST", berlinOffice, cet(3, 27, 17), cet(3, 30, 10), 2 * time.Hour},
+		{"always counts wall-clock time", Always(), msk(4, 25, 9, 0), msk(4, 26, 23, 0), 38 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.Elapsed(tt.from, tt.to); got != tt.want {
+				t.Errorf("Elapsed(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarIsWorkingTime(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	msk := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, moscow)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	office := mustCalendar(t, &domain.WorkSchedule{
+		Timezone:  "Europe/Moscow",
+		WorkStart: "10:00",
+		WorkEnd:   "19:00",
+	}, nil, "2026-05-01")
+	evenings := mustCalendar(t, &domain.WorkSchedule{WorkStart: "20:00", WorkEnd: "24:00", WorkDays: everyDay}, nil)
+	// Пользователь работает по Токио, рабочие часы берутся у команды
+	remote := mustCalendar(t,
+		&domain.WorkSchedule{Timezone: "Europe/Moscow", WorkStart: "10:00", WorkEnd: "19:00"},
+		&domain.WorkSchedule{Timezone: "Asia/Tokyo"},
+	)
+
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		at       time.Time
+		want     bool
+	}{
+		{"start of the day is included", office, msk(4, 27, 10, 0), true},
+		{"before the start", office, msk(4, 27, 9, 59), false},
+		{"end of the day is excluded", office, msk(4, 27, 19, 0), false},
+		{"weekend", office, msk(4, 25, 12, 0), false},
+		{"holiday", office, msk(5, 1, 12, 0), false},
+		{"instant in another zone", office, utc(4, 27, 9, 0), true},
+		{"until midnight", evenings, utc(4, 27, 23, 59), true},
+		{"midnight belongs to the next day", evenings, utc(4, 28, 0, 0), false},
+		{"user timezone overrides the team", remote, utc(4, 27, 2, 0), true},
+		{"team timezone is not used for the user", remote, msk(4, 27, 14, 0), false},
+		{"always", Always(), msk(4, 25, 3, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.IsWorkingTime(tt.at); got != tt.want {
+				t.Errorf("IsWorkingTime(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule domain.WorkSchedule
+	}{
+		{"unknown timezone", domain.WorkSchedule{Timezone: "Mars/Olympus"}},
+		{"start after end", domain.WorkSchedule{WorkStart: "18:00", WorkEnd: "09:00"}},
+		{"empty working day", domain.WorkSchedule{WorkStart: "09:00", WorkEnd: "09:00"}},
+		{"work day out of range", domain.WorkSchedule{WorkDays: []int{0}}},
+		{"minutes past 24:00", domain.WorkSchedule{WorkEnd: "24:30"}},
+		{"hours without padding", domain.WorkSchedule{WorkStart: "9:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(&tt.schedule, nil, nil); err == nil {
+				t.Error("New accepted an invalid schedule")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package businesstime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+type teamCalendar struct {
+	schedule *domain.WorkSchedule
+	holidays []domain.Holiday
+}
+
+// Resolver загружает и кеширует календари команд и пользователей.
+// Кеш не обновляется, поэтому Resolver создаётся на один проход планировщика
+// или один расчёт статистики.
+type Resolver struct {
+	repo      repository.ScheduleRepository
+	teams     map[string]teamCalendar
+	users     map[string]*domain.WorkSchedule
+	calendars map[[2]string]*Calendar
+}
+
+func NewResolver(repo repository.ScheduleRepository) *Resolver {
+	return &Resolver{
+		repo:      repo,
+		teams:     map[string]teamCalendar{},
+		users:     map[string]*domain.WorkSchedule{},
+		calendars: map[[2]string]*Calendar{},
+	}
+}
+
+// For возвращает календарь пользователя в команде; пустой userID - календарь команды.
+// Некорректное сохранённое расписание не останавливает планировщики:
+// оно логируется, и время считается без выходных.
+func (r *Resolver) For(ctx context.Context, teamName, userID string) (*Calendar, error) {
+	key := [2]string{teamName, userID}
+	if calendar, ok := r.calendars[key]; ok {
+		return calendar, nil
+	}
+
+	team, err := r.team(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	var user *domain.WorkSchedule
+	if userID != "" {
+		if user, err = r.user(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	calendar, err := New(team.schedule, user, team.holidays)
+	if err != nil {
+		logger.FromContext(ctx).Warn("invalid work schedule, counting wall-clock time",
+			"team_name", teamName,
+			"user_id", userID,
+			"error", err,
+		)
+		calendar = Always()
+	}
+	r.calendars[key] = calendar
+	return calendar, nil
+}
+
+func (r *Resolver) team(ctx context.Context, teamName string) (teamCalendar, error) {
+	if team, ok := r.teams[teamName]; ok {
+		return team, nil
+	}
+
+	var team teamCalendar
+	if teamName != "" {
+		schedule, err := r.repo.GetTeamSchedule(ctx, teamName)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return teamCalendar{}, err
+		}
+		holidays, err := r.repo.ListHolidays(ctx, teamName)
+		if err != nil {
+			return teamCalendar{}, err
+		}
+		team = teamCalendar{schedule: schedule, holidays: holidays}
+	}
+	r.teams[teamName] = team
+	return team, nil
+}
+
+func (r *Resolver) user(ctx context.Context, userID string) (*domain.WorkSchedule, error) {
+	if schedule, ok := r.users[userID]; ok {
+		return schedule, nil
+	}
+
+	schedule, err := r.repo.GetUserSchedule(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	r.users[userID] = schedule
+	return schedule, nil
+}
@@ -0,0 +1,149 @@
+package digest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// Notifier доставляет сводку пользователю по его каналам уведомлений
+type Notifier interface {
+	NotifyDigest(ctx context.Context, digest domain.ReviewDigest) error
+}
+
+// Job раз в день в заданное пользователем время отправляет сводку открытых PR,
+// где он назначен ревьювером
+type Job struct {
+	repo     repository.DigestRepository
+	userRepo repository.UserRepository
+	notifier Notifier
+	interval time.Duration
+	// Ссылка на PR без связи с GitHub/GitLab, {id} заменяется идентификатором PR
+	linkTemplate string
+}
+
+func NewJob(repo repository.DigestRepository, userRepo repository.UserRepository, notifier Notifier, interval time.Duration, linkTemplate string) *Job {
+	return &Job{
+		repo:         repo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+		interval:     interval,
+		linkTemplate: linkTemplate,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.sendDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Job) sendDue(ctx context.Context, now time.Time) {
+	subs, err := j.repo.ListEnabled(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("digest list failed", "error", err)
+		}
+		return
+	}
+
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		date, ok := dueDate(sub, now)
+		if !ok {
+			continue
+		}
+		if err := j.send(ctx, sub.UserID, date); err != nil {
+			metrics.IncDigest("failed")
+			logger.FromContext(ctx).Warn("digest failed", "user_id", sub.UserID, "date", date, "error", err)
+		}
+	}
+}
+
+// send отправляет сводку за локальную дату date. Без открытых PR сводка
+// не отправляется, но день всё равно считается обработанным.
+func (j *Job) send(ctx context.Context, userID, date string) error {
+	prs, err := j.userRepo.GetPullRequests(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// GetPullRequests возвращает PR от старых к новым
+	open := make([]domain.PullRequestShort, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		if pr.URL == "" && j.linkTemplate != "" {
+			pr.URL = strings.ReplaceAll(j.linkTemplate, "{id}", pr.PullRequestID)
+		}
+		open = append(open, pr)
+	}
+
+	if len(open) > 0 {
+		// Повторная постановка той же сводки другим экземпляром не создаёт дублей
+		err := j.notifier.NotifyDigest(ctx, domain.ReviewDigest{
+			UserID:       userID,
+			Date:         date,
+			PullRequests: open,
+		})
+		if err != nil {
+			return err
+		}
+		metrics.IncDigest("sent")
+	} else {
+		metrics.IncDigest("empty")
+	}
+
+	return j.repo.MarkSent(ctx, userID, date)
+}
+
+// dueDate возвращает локальную дату сводки, если она ещё не отправлена,
+// сегодня день отправки и время отправки уже наступило
+func dueDate(sub domain.DigestSubscription, now time.Time) (string, bool) {
+	location, err := time.LoadLocation(sub.Timezone)
+	if err != nil {
+		return "", false
+	}
+	sendAt, err := businesstime.ParseClock(sub.SendAt)
+	if err != nil {
+		return "", false
+	}
+
+	local := now.In(location)
+	date := local.Format(time.DateOnly)
+	if sub.LastSentOn != nil && *sub.LastSentOn >= date {
+		return "", false
+	}
+
+	// Дни недели по ISO: воскресенье - 7
+	weekday := int(local.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	if !slices.Contains(sub.Days, weekday) {
+		return "", false
+	}
+
+	return date, local.Hour()*60+local.Minute() >= sendAt
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Role -- enum для роли владельца токена
+type Role string
+
+const (
+	// RoleAdmin - полный доступ: команды, активность, PR, управление токенами
+	RoleAdmin Role = "admin"
+	// RoleUser - только чтение своих ревью
+	RoleUser Role = "user"
+)
+
+// APIToken - токен доступа к API. В базе хранится только хэш,
+// сам токен возвращается один раз при создании.
+type APIToken struct {
+	TokenID   string     `json:"token_id"`
+	Name      string     `json:"name"`
+	Role      Role       `json:"role"`
+	UserID    string     `json:"user_id,omitempty"`
+	Token     string     `json:"token,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Identity - кто выполняет запрос
+type Identity struct {
+	Role   Role
+	UserID string
+	// Subject - имя токена API или sub из JWT
+	Subject string
+	TokenID string
+}
+
+func (i *Identity) IsAdmin() bool {
+	return i != nil && i.Role == RoleAdmin
+}
+
+// Actor - кем подписывать действие в истории и событиях
+func (i *Identity) Actor() string {
+	if i == nil {
+		return ""
+	}
+	if i.UserID != "" {
+		return i.UserID
+	}
+	return i.Subject
+}
@@ -7,6 +7,7 @@ import (
 
 type ErrorResponse struct {
 	ErrorContent ErrorBody `json:"error"`
+	RequestID    string    `json:"request_id,omitempty"`
 }
 
 func (e ErrorResponse) Error() string {
@@ -22,13 +23,19 @@ type ErrorBody struct {
 type ErrorCode string
 
 const (
-	ErrCodeTeamExists  ErrorCode = "TEAM_EXISTS"
-	ErrCodePRExists    ErrorCode = "PR_EXISTS"
-	ErrCodePRMerged    ErrorCode = "PR_MERGED"
-	ErrCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
-	ErrCodeNoCandidate ErrorCode = "NO_CANDIDATE"
-	ErrCodeNotFound    ErrorCode = "NOT_FOUND"
-	ErrUnknown         ErrorCode = "UNKNOWN ERROR"
+	ErrCodeTeamExists          ErrorCode = "TEAM_EXISTS"
+	ErrCodePRExists            ErrorCode = "PR_EXISTS"
+	ErrCodePRMerged            ErrorCode = "PR_MERGED"
+	ErrCodeNotAssigned         ErrorCode = "NOT_ASSIGNED"
+	ErrCodeNoCandidate         ErrorCode = "NO_CANDIDATE"
+	ErrCodeNotFound            ErrorCode = "NOT_FOUND"
+	ErrCodeBadRequest          ErrorCode = "BAD_REQUEST"
+	ErrCodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden           ErrorCode = "FORBIDDEN"
+	ErrCodeRateLimited         ErrorCode = "RATE_LIMITED"
+	ErrCodeIdempotencyMismatch ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeRequestInProgress   ErrorCode = "REQUEST_IN_PROGRESS"
+	ErrUnknown                 ErrorCode = "UNKNOWN ERROR"
 )
 
 var ErrNotFound = errors.New("not found")
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType -- enum для типов доменных событий
+type EventType string
+
+const (
+	EventPRCreated          EventType = "PR_CREATED"
+	EventReviewerAssigned   EventType = "REVIEWER_ASSIGNED"
+	EventReviewerReassigned EventType = "REVIEWER_REASSIGNED"
+	EventPRMerged           EventType = "PR_MERGED"
+	EventUserDeactivated    EventType = "USER_DEACTIVATED"
+	EventUserActivated      EventType = "USER_ACTIVATED"
+)
+
+// Event - доменное событие из outbox
+type Event struct {
+	ID          int64           `json:"id"`
+	Type        EventType       `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	// Номер доставки: растёт в порядке доставки событий, 0 - ещё не доставлено
+	Sequence int64 `json:"sequence,omitempty"`
+
+	// Неудачные попытки доставки и подписчики, которые событие уже получили
+	Attempts    int      `json:"-"`
+	DeliveredTo []string `json:"-"`
+}
+
+// PRCreatedPayload - данные события PR_CREATED
+type PRCreatedPayload struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	TeamName        string   `json:"team_name"`
+	Reviewers       []string `json:"assigned_reviewers"`
+}
+
+// ReviewerAssignedPayload - данные события REVIEWER_ASSIGNED
+type ReviewerAssignedPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+// ReviewerReassignedPayload - данные события REVIEWER_REASSIGNED
+type ReviewerReassignedPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	Reason        string `json:"reason,omitempty"`
+	Actor         string `json:"actor,omitempty"`
+}
+
+// PRMergedPayload - данные события PR_MERGED
+type PRMergedPayload struct {
+	PullRequestID string    `json:"pull_request_id"`
+	AuthorID      string    `json:"author_id"`
+	TeamName      string    `json:"team_name"`
+	MergedAt      time.Time `json:"merged_at"`
+	MergedBy      string    `json:"merged_by,omitempty"`
+}
+
+// UserDeactivatedPayload - данные события USER_DEACTIVATED
+type UserDeactivatedPayload struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// UserActivatedPayload - данные события USER_ACTIVATED
+type UserActivatedPayload struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
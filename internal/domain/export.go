@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+// ExportFilter - фильтры выгрузок /export/*; нулевые значения не ограничивают выборку
+type ExportFilter struct {
+	TeamName string
+	// Полуинтервал [From, To) по дате создания PR или назначения ревьювера
+	From   *time.Time
+	To     *time.Time
+	Status PRStatus
+}
+
+// PullRequestExport - строка выгрузки /export/prs
+type PullRequestExport struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	TeamName        string     `json:"team_name"`
+	Status          PRStatus   `json:"status"`
+	Reviewers       []string   `json:"assigned_reviewers"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	MergedAt        *time.Time `json:"merged_at,omitempty"`
+}
+
+// AssignmentExport - строка выгрузки /export/assignments: текущий ревьювер PR
+type AssignmentExport struct {
+	PullRequestID   string         `json:"pull_request_id"`
+	PullRequestName string         `json:"pull_request_name"`
+	TeamName        string         `json:"team_name"`
+	Status          PRStatus       `json:"status"`
+	ReviewerID      string         `json:"reviewer_id"`
+	AssignedAt      time.Time      `json:"assigned_at"`
+	Verdict         *ReviewVerdict `json:"verdict,omitempty"`
+	VerdictAt       *time.Time     `json:"verdict_at,omitempty"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Forge -- enum для поддерживаемых хостингов кода
+type Forge string
+
+const (
+	ForgeGitHub Forge = "github"
+	ForgeGitLab Forge = "gitlab"
+)
+
+// ForgeUser - соответствие пользователя GitHub/GitLab пользователю сервиса
+type ForgeUser struct {
+	Forge       Forge  `json:"forge"`
+	Username    string `json:"forge_username"`
+	ForgeUserID int64  `json:"forge_user_id,omitempty"`
+	UserID      string `json:"user_id"`
+}
+
+// ForgePullRequest - связь PR на GitHub/GitLab с PR сервиса
+type ForgePullRequest struct {
+	Forge         Forge  `json:"forge"`
+	Repository    string `json:"repository"`
+	Number        int64  `json:"number"`
+	PullRequestID string `json:"pull_request_id"`
+	URL           string `json:"url"`
+}
+
+// ForgeAction -- enum для действий с PR, которые сервис обрабатывает
+type ForgeAction string
+
+const (
+	ForgeActionOpened ForgeAction = "opened"
+	ForgeActionMerged ForgeAction = "merged"
+	ForgeActionClosed ForgeAction = "closed"
+)
+
+// ForgePREvent - событие PR из входящего вебхука GitHub/GitLab
+type ForgePREvent struct {
+	Forge          Forge
+	Action         ForgeAction
+	Repository     string
+	Number         int64
+	Title          string
+	URL            string
+	AuthorUsername string
+}
+
+// ForgeSyncFailure - неудачная попытка передать ревьюверов PR на GitHub/GitLab
+type ForgeSyncFailure struct {
+	ID            int64     `json:"id"`
+	PullRequestID string    `json:"pull_request_id"`
+	Forge         Forge     `json:"forge"`
+	Repository    string    `json:"repository"`
+	Number        int64     `json:"number"`
+	EventID       int64     `json:"event_id"`
+	Error         string    `json:"error"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+// SchemaStatus - версия схемы базы относительно встроенных в бинарник миграций
+type SchemaStatus struct {
+	// 0 - миграции не применялись
+	Version  uint `json:"version"`
+	Expected uint `json:"expected"`
+	// Последняя миграция упала на середине
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// Readiness - ответ /health/ready
+type Readiness struct {
+	Ready  bool         `json:"ready"`
+	Schema SchemaStatus `json:"schema"`
+	// Почему экземпляр не готов принимать трафик
+	Reason string `json:"reason,omitempty"`
+}
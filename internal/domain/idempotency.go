@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord - сохранённый ответ на запрос с Idempotency-Key.
+// StatusCode == 0 - первый запрос с этим ключом ещё выполняется.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
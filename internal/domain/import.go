@@ -0,0 +1,33 @@
+package domain
+
+// ImportRow - строка импорта команд: пользователь и команда, в которой он должен состоять
+type ImportRow struct {
+	TeamName string `json:"team" yaml:"team"`
+	UserID   string `json:"user_id" yaml:"user_id"`
+	Username string `json:"username" yaml:"username"`
+	IsActive bool   `json:"is_active" yaml:"is_active"`
+}
+
+// ImportChange - изменение пользователя при импорте
+type ImportChange struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	// Прежние команды пользователя, для moved
+	FromTeams []string `json:"from_teams,omitempty"`
+	// Прежнее имя, если оно меняется
+	OldUsername string `json:"old_username,omitempty"`
+}
+
+// ImportResult - разница между файлом импорта и текущим состоянием.
+// Пользователь может попасть в несколько списков, например moved и deactivated.
+type ImportResult struct {
+	DryRun       bool           `json:"dry_run"`
+	TeamsCreated []string       `json:"teams_created"`
+	Created      []ImportChange `json:"created"`
+	Updated      []ImportChange `json:"updated"`
+	Moved        []ImportChange `json:"moved"`
+	Deactivated  []ImportChange `json:"deactivated"`
+	// Пользователи, которых импорт не меняет
+	Unchanged int `json:"unchanged"`
+}
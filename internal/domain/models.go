@@ -15,6 +15,8 @@ type TeamMember struct {
 type Team struct {
 	TeamName string       `json:"team_name"`
 	Members  []TeamMember `json:"members"`
+	// SLA ревью в минутах, nil - без автоматической эскалации
+	ReviewSLAMinutes *int `json:"review_sla_minutes,omitempty"`
 }
 
 // User соответствует components.schemas.User
@@ -36,18 +38,24 @@ type PullRequest struct {
 	PullRequestID     string     `json:"pull_request_id"`
 	PullRequestName   string     `json:"pull_request_name"`
 	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name"`
 	Status            PRStatus   `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	// Вердикты по ревьюверам; кто ещё не вынес вердикт, отсутствует
+	Verdicts map[string]ReviewVerdict `json:"verdicts,omitempty"`
 }
 
 // PullRequestShort соответствует components.schemas.PullRequestShort
 type PullRequestShort struct {
-	PullRequestID   string   `json:"pull_request_id"`
-	PullRequestName string   `json:"pull_request_name"`
-	AuthorID        string   `json:"author_id"`
-	Status          PRStatus `json:"status"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Status          PRStatus   `json:"status"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	// Ссылка на PR в GitHub/GitLab, если он связан
+	URL string `json:"url,omitempty"`
 }
 
 // PRStatus -- enum для статуса PR
@@ -57,3 +65,63 @@ const (
 	PRStatusOpen   PRStatus = "OPEN"
 	PRStatusMerged PRStatus = "MERGED"
 )
+
+// ReviewVerdict -- enum для вердикта ревьювера
+type ReviewVerdict string
+
+const (
+	ReviewVerdictApproved         ReviewVerdict = "APPROVED"
+	ReviewVerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+)
+
+// OverdueReview - ревью без вердикта, которое может превышать SLA команды
+type OverdueReview struct {
+	PullRequestID    string
+	TeamName         string
+	ReviewerID       string
+	AssignedAt       time.Time
+	ReviewSLAMinutes int
+}
+
+// ReviewerEventType -- enum для событий истории назначений ревьюверов
+type ReviewerEventType string
+
+const (
+	ReviewerEventAssigned       ReviewerEventType = "ASSIGNED"
+	ReviewerEventReassignedFrom ReviewerEventType = "REASSIGNED_FROM"
+	ReviewerEventReassignedTo   ReviewerEventType = "REASSIGNED_TO"
+)
+
+// ReviewerHistoryEntry - запись истории назначений ревьюверов PR
+type ReviewerHistoryEntry struct {
+	PullRequestID string            `json:"pull_request_id"`
+	Event         ReviewerEventType `json:"event"`
+	UserID        string            `json:"user_id"`
+	RelatedUserID string            `json:"related_user_id,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	Actor         string            `json:"actor,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// WorkSchedule - рабочие часы команды или пользователя. Незаполненные поля
+// пользователя берутся из расписания команды, команды - из значений по умолчанию
+type WorkSchedule struct {
+	Timezone  string `json:"timezone,omitempty"`
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	// Дни недели по ISO: 1 - понедельник, 7 - воскресенье
+	WorkDays []int `json:"work_days,omitempty"`
+}
+
+// Holiday - нерабочий день в календаре команды
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name,omitempty"`
+}
+
+// TeamCalendar - расписание и праздники команды
+type TeamCalendar struct {
+	TeamName string        `json:"team_name"`
+	Schedule *WorkSchedule `json:"schedule,omitempty"`
+	Holidays []Holiday     `json:"holidays"`
+}
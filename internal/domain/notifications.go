@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+)
+
+// NotificationChannel -- enum для канала уведомлений
+type NotificationChannel string
+
+const (
+	NotificationEmail      NotificationChannel = "EMAIL"
+	NotificationSlack      NotificationChannel = "SLACK"
+	NotificationMattermost NotificationChannel = "MATTERMOST"
+)
+
+// NotificationKind -- enum для вида уведомления, у каждого свой шаблон
+type NotificationKind string
+
+const (
+	NotificationReviewerAssigned   NotificationKind = "REVIEWER_ASSIGNED"
+	NotificationReviewerReassigned NotificationKind = "REVIEWER_REASSIGNED"
+	NotificationReviewReminder     NotificationKind = "REVIEW_REMINDER"
+	NotificationDailyDigest        NotificationKind = "DAILY_DIGEST"
+)
+
+// NotificationPreference - канал уведомлений пользователя
+type NotificationPreference struct {
+	UserID  string              `json:"user_id"`
+	Channel NotificationChannel `json:"channel"`
+	// Адрес почты или URL входящего вебхука Slack/Mattermost
+	Address string `json:"address"`
+	Enabled bool   `json:"enabled"`
+	// Виды уведомлений, пусто - все
+	Kinds     []NotificationKind `json:"kinds,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NotificationStatus -- enum для статуса доставки уведомления
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "PENDING"
+	NotificationSent    NotificationStatus = "SENT"
+	NotificationFailed  NotificationStatus = "FAILED"
+)
+
+// NotificationDelivery - уведомление в очереди или журнале доставки
+type NotificationDelivery struct {
+	DeliveryID int64               `json:"delivery_id"`
+	UserID     string              `json:"user_id"`
+	Channel    NotificationChannel `json:"channel"`
+	Address    string              `json:"address"`
+	Kind       NotificationKind    `json:"kind"`
+	DedupKey   string              `json:"-"`
+	Subject    string              `json:"subject"`
+	Body       string              `json:"body"`
+	// HTML-версия для почты, в журнале не отдаётся
+	HTML      string             `json:"-"`
+	Status    NotificationStatus `json:"status"`
+	Attempts  int                `json:"attempts"`
+	LastError string             `json:"last_error,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	SentAt    *time.Time         `json:"sent_at,omitempty"`
+}
+
+// DigestSubscription - подписка пользователя на ежедневную сводку открытых ревью
+type DigestSubscription struct {
+	UserID  string `json:"user_id"`
+	Enabled bool   `json:"enabled"`
+	// Время отправки "HH:MM" в часовом поясе Timezone
+	SendAt   string `json:"send_at"`
+	Timezone string `json:"timezone"`
+	// Дни недели по ISO (1 - понедельник)
+	Days []int `json:"days"`
+	// Локальная дата последней сводки, YYYY-MM-DD
+	LastSentOn *string   `json:"last_sent_on,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ReviewDigest - сводка открытых PR, ожидающих ревью пользователя
+type ReviewDigest struct {
+	UserID string
+	// Локальная дата сводки, YYYY-MM-DD
+	Date         string
+	PullRequests []PullRequestShort
+}
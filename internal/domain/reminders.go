@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReviewReminder - напоминание ревьюверу о зависшем открытом PR
+type ReviewReminder struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	TeamName        string    `json:"team_name"`
+	ReviewerID      string    `json:"reviewer_id"`
+	OpenedAt        time.Time `json:"opened_at"`
+	// Номер напоминания этому ревьюверу по этому PR, начиная с 1
+	Count int `json:"count"`
+}
+
+// ReminderRecord - учёт напоминаний одному ревьюверу по открытому PR
+type ReminderRecord struct {
+	PullRequestID  string     `json:"pull_request_id"`
+	ReviewerID     string     `json:"reviewer_id"`
+	TeamName       string     `json:"team_name"`
+	ReminderCount  int        `json:"reminder_count"`
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// TeamReminderThreshold - порог команды, после которого открытый PR считается зависшим
+type TeamReminderThreshold struct {
+	TeamName string `json:"team_name"`
+	// nil - используется порог из конфигурации
+	ThresholdMinutes *int `json:"threshold_minutes,omitempty"`
+}
+
+// ReminderSchedulerStatus - состояние планировщика напоминаний в этом экземпляре сервиса
+type ReminderSchedulerStatus struct {
+	Running                 bool       `json:"running"`
+	CheckIntervalSeconds    int64      `json:"check_interval_seconds"`
+	DefaultThresholdMinutes int64      `json:"default_threshold_minutes"`
+	RepeatIntervalMinutes   int64      `json:"repeat_interval_minutes"`
+	LastRunAt               *time.Time `json:"last_run_at,omitempty"`
+	LastRunSent             int        `json:"last_run_sent"`
+	LastRunFailed           int        `json:"last_run_failed"`
+	LastError               string     `json:"last_error,omitempty"`
+	SentTotal               int64      `json:"sent_total"`
+	FailedTotal             int64      `json:"failed_total"`
+}
+
+// ReminderStatus - ответ /reminders/status
+type ReminderStatus struct {
+	Scheduler ReminderSchedulerStatus `json:"scheduler"`
+	Teams     []TeamReminderThreshold `json:"teams"`
+	Reminders []ReminderRecord        `json:"reminders"`
+}
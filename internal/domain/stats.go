@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+// Percentiles - перцентили длительности в секундах по методу ближайшего ранга.
+// Длительности в рабочем времени: ночи, выходные и праздники не учитываются.
+type Percentiles struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_seconds"`
+	P90   float64 `json:"p90_seconds"`
+	P99   float64 `json:"p99_seconds"`
+}
+
+// TeamTurnaround - время от создания до слияния PR команды
+type TeamTurnaround struct {
+	TeamName    string      `json:"team_name"`
+	TimeToMerge Percentiles `json:"time_to_merge"`
+}
+
+// AuthorTurnaround - время от создания до слияния PR автора
+type AuthorTurnaround struct {
+	AuthorID    string      `json:"author_id"`
+	TimeToMerge Percentiles `json:"time_to_merge"`
+}
+
+// ReviewerTurnaround - время от назначения ревьювера до его вердикта
+type ReviewerTurnaround struct {
+	ReviewerID    string      `json:"reviewer_id"`
+	TimeToVerdict Percentiles `json:"time_to_verdict"`
+}
+
+// TurnaroundStats - ответ /stats/turnaround. PR попадают в выборку по дате слияния,
+// вердикты - по дате вердикта.
+type TurnaroundStats struct {
+	// Даты YYYY-MM-DD по UTC, обе включительно
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	TeamName    string               `json:"team_name,omitempty"`
+	Teams       []TeamTurnaround     `json:"teams"`
+	Authors     []AuthorTurnaround   `json:"authors"`
+	Reviewers   []ReviewerTurnaround `json:"reviewers"`
+	GeneratedAt time.Time            `json:"generated_at"`
+}
+
+// TurnaroundSample - одна длительность в выборке /stats/turnaround:
+// PR от создания до слияния или ревью от назначения до вердикта
+type TurnaroundSample struct {
+	TeamName string
+	// Автор PR или ревьювер, вынесший вердикт
+	UserID string
+	Start  time.Time
+	End    time.Time
+	// Рабочее время между Start и End, заполняет сервис
+	Seconds float64
+}
@@ -0,0 +1,131 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+const (
+	// Actor - кем подписаны переназначения по SLA в истории и событиях
+	Actor = "sla-escalation"
+)
+
+// Escalator периодически переназначает ревью, по которым ревьювер не вынес
+// вердикт за SLA команды. SLA считается в рабочем времени ревьювера.
+type Escalator struct {
+	prRepo    repository.PrRepository
+	schedules repository.ScheduleRepository
+	interval  time.Duration
+}
+
+func NewEscalator(prRepo repository.PrRepository, schedules repository.ScheduleRepository, interval time.Duration) *Escalator {
+	return &Escalator{
+		prRepo:    prRepo,
+		schedules: schedules,
+		interval:  interval,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (e *Escalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.escalate(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// escalate переназначает ревью, превысившие SLA. Ревью без кандидата на замену
+// остаются просроченными и повторяются на следующем проходе.
+func (e *Escalator) escalate(ctx context.Context) {
+	reviews, err := e.prRepo.ListOverdue(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("overdue reviews lookup failed", "error", err)
+		}
+		return
+	}
+
+	calendars := businesstime.NewResolver(e.schedules)
+	now := time.Now()
+	for _, review := range reviews {
+		if ctx.Err() != nil {
+			return
+		}
+
+		calendar, err := calendars.For(ctx, review.TeamName, review.ReviewerID)
+		if err != nil {
+			logger.FromContext(ctx).Error("review calendar lookup failed", "pr_id", review.PullRequestID, "error", err)
+			continue
+		}
+		sla := time.Duration(review.ReviewSLAMinutes) * time.Minute
+		if calendar.Elapsed(review.AssignedAt, now) < sla {
+			continue
+		}
+
+		e.reassign(ctx, review)
+	}
+}
+
+func (e *Escalator) reassign(ctx context.Context, review domain.OverdueReview) {
+	sla := time.Duration(review.ReviewSLAMinutes) * time.Minute
+	reason := fmt.Sprintf("review SLA of %s exceeded without a verdict", formatSLA(sla))
+
+	newReviewerID, reassigned, err := e.prRepo.ReassignOverdue(ctx, review, reason, Actor)
+	if err != nil {
+		// Ошибки репозитория текстовые, как и в PrService.ReassignPR
+		switch {
+		case strings.Contains(err.Error(), "no available reviewers in team"):
+			metrics.IncEscalation("no_candidate")
+			logger.FromContext(ctx).Info("review SLA exceeded, no replacement candidate",
+				"pr_id", review.PullRequestID,
+				"reviewer_id", review.ReviewerID,
+				"team_name", review.TeamName,
+			)
+		default:
+			metrics.IncEscalation("failed")
+			logger.FromContext(ctx).Error("review SLA escalation failed",
+				"pr_id", review.PullRequestID,
+				"reviewer_id", review.ReviewerID,
+				"error", err,
+			)
+		}
+		return
+	}
+	if !reassigned {
+		// Ревью изменилось после выборки: вердикт вынесен, ревьювер заменён или PR смерджен
+		return
+	}
+
+	metrics.IncEscalation("reassigned")
+	logger.FromContext(ctx).Info("review SLA exceeded, reviewer reassigned",
+		"pr_id", review.PullRequestID,
+		"old_reviewer_id", review.ReviewerID,
+		"new_reviewer_id", newReviewerID,
+		"team_name", review.TeamName,
+		"assigned_at", review.AssignedAt,
+	)
+}
+
+// formatSLA печатает SLA без нулевых минут и секунд: 24h, 90m
+func formatSLA(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int64(d/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int64(d/time.Minute))
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Broadcaster раздаёт доставленные события подключённым клиентам (стримы gRPC, SSE).
+// Клиент, не успевающий читать, отключается: его канал закрывается.
+type Broadcaster struct {
+	buffer int
+
+	mu          sync.Mutex
+	subscribers map[chan domain.Event]struct{}
+	closed      bool
+}
+
+func NewBroadcaster(buffer int) *Broadcaster {
+	return &Broadcaster{
+		buffer:      buffer,
+		subscribers: make(map[chan domain.Event]struct{}),
+	}
+}
+
+// Subscribe возвращает канал событий и функцию отписки
+func (b *Broadcaster) Subscribe() (<-chan domain.Event, func()) {
+	ch := make(chan domain.Event, b.buffer)
+
+	b.mu.Lock()
+	if b.closed {
+		close(ch)
+	} else {
+		b.subscribers[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Broadcast отдаёт событие клиентам, регистрируется через Dispatcher.AfterDispatch.
+// Не блокируется, чтобы медленные клиенты не задерживали outbox.
+func (b *Broadcaster) Broadcast(event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close отключает всех клиентов, например при остановке сервера
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Handler - подписчик на доменные события. Доставка at-least-once: событие
+// повторяется, если экземпляр упал между обработкой и отметкой о ней,
+// поэтому обработчик должен быть идемпотентным.
+type Handler func(ctx context.Context, event domain.Event) error
+
+type subscription struct {
+	name    string
+	types   map[domain.EventType]struct{}
+	handler Handler
+}
+
+// Bus - внутрипроцессная шина доменных событий
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe регистрирует обработчик. Без типов обработчик получает все события.
+// По name учитывается, кому событие уже доставлено, поэтому имя не должно меняться.
+func (b *Bus) Subscribe(name string, handler Handler, types ...domain.EventType) {
+	sub := subscription{
+		name:    name,
+		handler: handler,
+	}
+	if len(types) > 0 {
+		sub.types = make(map[domain.EventType]struct{}, len(types))
+		for _, t := range types {
+			sub.types[t] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, sub)
+}
+
+// Publish передаёт событие подходящим подписчикам, кроме уже получивших его
+// (event.DeliveredTo), и возвращает имена тех, кто обработал его сейчас.
+// Ошибки подписчиков объединяются, чтобы диспетчер повторил доставку только им.
+func (b *Bus) Publish(ctx context.Context, event domain.Event) ([]string, error) {
+	b.mu.RLock()
+	subscriptions := make([]subscription, len(b.subscriptions))
+	copy(subscriptions, b.subscriptions)
+	b.mu.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "events.Publish",
+		attribute.Int64("event.id", event.ID),
+		attribute.String("event.type", string(event.Type)),
+		attribute.Int("event.attempts", event.Attempts),
+	)
+	defer span.End()
+
+	// Подписчики логируют с идентификатором события
+	ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("event_id", event.ID, "event_type", event.Type))
+
+	var (
+		delivered []string
+		errs      []error
+	)
+	for _, sub := range subscriptions {
+		if sub.types != nil {
+			if _, ok := sub.types[event.Type]; !ok {
+				continue
+			}
+		}
+		if slices.Contains(event.DeliveredTo, sub.name) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		delivered = append(delivered, sub.name)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return delivered, err
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+const (
+	// Время, на которое пачка событий захватывается одним диспетчером
+	claimLease = time.Minute
+	maxBackoff = 5 * time.Minute
+)
+
+// Dispatcher периодически разбирает outbox и доставляет события в Bus
+type Dispatcher struct {
+	outbox      repository.OutboxRepository
+	bus         *Bus
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	baseBackoff time.Duration
+
+	// Вызываются после фиксации доставки, см. AfterDispatch
+	afterDispatch []func(event domain.Event)
+}
+
+func NewDispatcher(outbox repository.OutboxRepository, bus *Bus, interval time.Duration, batchSize, maxAttempts int, baseBackoff time.Duration) *Dispatcher {
+	return &Dispatcher{
+		outbox:      outbox,
+		bus:         bus,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+	}
+}
+
+// AfterDispatch регистрирует fn, который получает событие с номером доставки
+// после того, как доставка зафиксирована в outbox. В отличие от подписчиков
+// шины fn вызывается для события ровно один раз и в порядке номеров,
+// поэтому через него событие отдаётся в живые потоки (SSE, gRPC).
+// Регистрировать до Run.
+func (d *Dispatcher) AfterDispatch(fn func(event domain.Event)) {
+	d.afterDispatch = append(d.afterDispatch, fn)
+}
+
+// Run блокируется до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain обрабатывает пачки событий, пока outbox не опустеет
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimedAt := time.Now()
+		events, err := d.outbox.Claim(ctx, d.batchSize, claimLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Error("outbox claim failed", "error", err)
+			}
+			return
+		}
+
+		for _, event := range events {
+			// Остаток пачки подхватит следующий захват, когда истечёт этот:
+			// так медленные подписчики не приводят к параллельной доставке
+			if time.Since(claimedAt) > claimLease/2 || ctx.Err() != nil {
+				return
+			}
+			d.dispatch(ctx, event)
+		}
+
+		if len(events) < d.batchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event domain.Event) {
+	delivered, err := d.bus.Publish(ctx, event)
+	if err == nil {
+		seq, err := d.outbox.MarkDispatched(ctx, event.ID, delivered)
+		if err != nil {
+			logger.FromContext(ctx).Error("outbox mark dispatched failed", "event_id", event.ID, "error", err)
+			return
+		}
+		event.Sequence = seq
+		for _, fn := range d.afterDispatch {
+			fn(event)
+		}
+		return
+	}
+
+	// Попытки считаются с единицы: текущая ещё не учтена в event.Attempts
+	attempt := event.Attempts + 1
+	var nextAttemptAt *time.Time
+	if attempt < d.maxAttempts {
+		next := time.Now().Add(d.backoff(attempt))
+		nextAttemptAt = &next
+	}
+
+	log := logger.FromContext(ctx).With("event_id", event.ID, "event_type", event.Type, "attempt", attempt, "error", err)
+	if nextAttemptAt == nil {
+		log.Error("outbox event is dead, attempts exhausted")
+	} else {
+		log.Warn("outbox event delivery failed")
+	}
+	if err := d.outbox.MarkFailed(ctx, event.ID, delivered, err.Error(), nextAttemptAt); err != nil {
+		logger.FromContext(ctx).Error("outbox mark failed failed", "event_id", event.ID, "error", err)
+	}
+}
+
+// backoff - экспоненциальная задержка перед попыткой attempt+1
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	b := d.baseBackoff
+	for i := 1; i < attempt && b < maxBackoff; i++ {
+		b *= 2
+	}
+	return min(b, maxBackoff)
+}
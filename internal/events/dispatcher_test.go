@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// fakeOutbox выдаёт номера доставки и запоминает отметки
+type fakeOutbox struct {
+	repository.OutboxRepository
+
+	seq        int64
+	dispatched map[int64]int64
+	failed     map[int64][]string
+}
+
+func newFakeOutbox() *fakeOutbox {
+	return &fakeOutbox{dispatched: map[int64]int64{}, failed: map[int64][]string{}}
+}
+
+func (r *fakeOutbox) MarkDispatched(_ context.Context, id int64, _ []string) (int64, error) {
+	r.seq++
+	r.dispatched[id] = r.seq
+	return r.seq, nil
+}
+
+func (r *fakeOutbox) MarkFailed(_ context.Context, id int64, delivered []string, _ string, _ *time.Time) error {
+	r.failed[id] = delivered
+	return nil
+}
+
+func TestDispatcherBroadcastsAfterDispatchOnly(t *testing.T) {
+	bus := NewBus()
+	bus.Subscribe("webhooks", func(_ context.Context, event domain.Event) error {
+		if event.ID == 2 {
+			return errors.New("receiver is down")
+		}
+		return nil
+	})
+	outbox := newFakeOutbox()
+	d := NewDispatcher(outbox, bus, time.Second, 10, 3, time.Second)
+
+	var broadcast []domain.Event
+	d.AfterDispatch(func(event domain.Event) { broadcast = append(broadcast, event) })
+
+	for _, id := range []int64{1, 2, 3} {
+		d.dispatch(context.Background(), domain.Event{ID: id, Type: domain.EventPRCreated})
+	}
+
+	// Событие 2 не доставлено и в поток не попадает, номера идут подряд
+	if len(broadcast) != 2 || broadcast[0].ID != 1 || broadcast[1].ID != 3 {
+		t.Fatalf("broadcast %+v, want events 1 and 3", broadcast)
+	}
+	if broadcast[0].Sequence != 1 || broadcast[1].Sequence != 2 {
+		t.Errorf("sequences %d, %d, want 1, 2", broadcast[0].Sequence, broadcast[1].Sequence)
+	}
+	if _, ok := outbox.failed[2]; !ok {
+		t.Error("event 2 not marked failed")
+	}
+
+	// Снова неудачный повтор тоже не попадает в поток
+	d.dispatch(context.Background(), domain.Event{ID: 2, Type: domain.EventPRCreated, Attempts: 1})
+	if len(broadcast) != 2 {
+		t.Fatalf("failed retry was broadcast: %+v", broadcast)
+	}
+}
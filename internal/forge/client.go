@@ -0,0 +1,138 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Client передаёт назначенных ревьюверов в PR на стороне GitHub/GitLab
+type Client interface {
+	// SetReviewers делает reviewers запрошенными ревьюверами PR и снимает removed
+	SetReviewers(ctx context.Context, pr domain.ForgePullRequest, reviewers, removed []domain.ForgeUser) error
+}
+
+// GitHubClient - клиент GitHub REST API
+type GitHubClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitHubClient(baseURL, token string, httpClient *http.Client) *GitHubClient {
+	return &GitHubClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+func (c *GitHubClient) SetReviewers(ctx context.Context, pr domain.ForgePullRequest, reviewers, removed []domain.ForgeUser) error {
+	endpoint := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, pr.Repository, pr.Number)
+
+	if len(removed) > 0 {
+		body := map[string][]string{"reviewers": logins(removed)}
+		if err := c.do(ctx, http.MethodDelete, endpoint, body); err != nil {
+			return err
+		}
+	}
+
+	if len(reviewers) > 0 {
+		body := map[string][]string{"reviewers": logins(reviewers)}
+		if err := c.do(ctx, http.MethodPost, endpoint, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *GitHubClient) do(ctx context.Context, method, endpoint string, body any) error {
+	req, err := newJSONRequest(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	return send(c.http, req)
+}
+
+// GitLabClient - клиент GitLab REST API
+type GitLabClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitLabClient(baseURL, token string, httpClient *http.Client) *GitLabClient {
+	return &GitLabClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+// SetReviewers заменяет список ревьюверов MR целиком, поэтому removed не используется
+func (c *GitLabClient) SetReviewers(ctx context.Context, pr domain.ForgePullRequest, reviewers, _ []domain.ForgeUser) error {
+	ids := make([]int64, 0, len(reviewers))
+	for _, r := range reviewers {
+		if r.ForgeUserID == 0 {
+			return fmt.Errorf("gitlab user %s has no forge_user_id", r.Username)
+		}
+		ids = append(ids, r.ForgeUserID)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d", c.baseURL, url.PathEscape(pr.Repository), pr.Number)
+	req, err := newJSONRequest(ctx, http.MethodPut, endpoint, map[string][]int64{"reviewer_ids": ids})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	return send(c.http, req)
+}
+
+func logins(users []domain.ForgeUser) []string {
+	result := make([]string, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.Username)
+	}
+	return result
+}
+
+func newJSONRequest(ctx context.Context, method, endpoint string, body any) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
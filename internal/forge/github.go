@@ -0,0 +1,88 @@
+package forge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+const (
+	GitHubEventHeader     = "X-GitHub-Event"
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+)
+
+// VerifyGitHubSignature проверяет заголовок X-Hub-Signature-256 (sha256=<hex HMAC тела>)
+func VerifyGitHubSignature(secret, signature string, body []byte) bool {
+	if secret == "" {
+		return false
+	}
+
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		Merged  bool   `json:"merged"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// ParseGitHubEvent разбирает вебхук GitHub. Для событий, которые сервис
+// не обрабатывает, возвращает nil без ошибки.
+func ParseGitHubEvent(eventName string, body []byte) (*domain.ForgePREvent, error) {
+	if eventName != "pull_request" {
+		return nil, nil
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var action domain.ForgeAction
+	switch payload.Action {
+	case "opened", "reopened":
+		action = domain.ForgeActionOpened
+	case "closed":
+		action = domain.ForgeActionClosed
+		if payload.PullRequest.Merged {
+			action = domain.ForgeActionMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	return &domain.ForgePREvent{
+		Forge:          domain.ForgeGitHub,
+		Action:         action,
+		Repository:     payload.Repository.FullName,
+		Number:         payload.Number,
+		Title:          payload.PullRequest.Title,
+		URL:            payload.PullRequest.HTMLURL,
+		AuthorUsername: payload.PullRequest.User.Login,
+	}, nil
+}
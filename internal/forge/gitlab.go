@@ -0,0 +1,73 @@
+package forge
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+const (
+	GitLabEventHeader = "X-Gitlab-Event"
+	GitLabTokenHeader = "X-Gitlab-Token"
+)
+
+// VerifyGitLabToken сравнивает секретный токен из заголовка X-Gitlab-Token
+func VerifyGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int64  `json:"iid"`
+		Title  string `json:"title"`
+		URL    string `json:"url"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// ParseGitLabEvent разбирает вебхук GitLab. Для событий, которые сервис
+// не обрабатывает, возвращает nil без ошибки.
+func ParseGitLabEvent(eventName string, body []byte) (*domain.ForgePREvent, error) {
+	if eventName != "Merge Request Hook" {
+		return nil, nil
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var action domain.ForgeAction
+	switch payload.ObjectAttributes.Action {
+	case "open", "reopen":
+		action = domain.ForgeActionOpened
+	case "merge":
+		action = domain.ForgeActionMerged
+	case "close":
+		action = domain.ForgeActionClosed
+	default:
+		return nil, nil
+	}
+
+	// При открытии MR пользователь события - его автор
+	return &domain.ForgePREvent{
+		Forge:          domain.ForgeGitLab,
+		Action:         action,
+		Repository:     payload.Project.PathWithNamespace,
+		Number:         payload.ObjectAttributes.IID,
+		Title:          payload.ObjectAttributes.Title,
+		URL:            payload.ObjectAttributes.URL,
+		AuthorUsername: payload.User.Username,
+	}, nil
+}
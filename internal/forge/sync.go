@@ -0,0 +1,126 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// Syncer передаёт назначения ревьюверов в PR на GitHub/GitLab.
+// Ошибки API сохраняются в forge_sync_failures и не откатывают назначение в сервисе.
+type Syncer struct {
+	forgeRepo repository.ForgeRepository
+	prRepo    repository.PrRepository
+	clients   map[domain.Forge]Client
+}
+
+func NewSyncer(forgeRepo repository.ForgeRepository, prRepo repository.PrRepository, clients map[domain.Forge]Client) *Syncer {
+	return &Syncer{
+		forgeRepo: forgeRepo,
+		prRepo:    prRepo,
+		clients:   clients,
+	}
+}
+
+// Handle - обработчик шины для PR_CREATED и REVIEWER_REASSIGNED
+func (s *Syncer) Handle(ctx context.Context, event domain.Event) error {
+	var prID string
+	var removedIDs []string
+
+	switch event.Type {
+	case domain.EventPRCreated:
+		var payload domain.PRCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		prID = payload.PullRequestID
+	case domain.EventReviewerReassigned:
+		var payload domain.ReviewerReassignedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		prID = payload.PullRequestID
+		removedIDs = []string{payload.OldReviewerID}
+	default:
+		return nil
+	}
+
+	link, err := s.forgeRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		// PR создан не через GitHub/GitLab: связь создаётся в одной транзакции
+		// с PR и его событиями, поэтому к моменту доставки она уже видна
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	client, ok := s.clients[link.Forge]
+	if !ok {
+		return nil
+	}
+
+	// Берём текущих ревьюверов, а не из события: событие могло устареть
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return err
+	}
+
+	forgeUsers, err := s.forgeRepo.GetUsersByUserIDs(ctx, link.Forge, slices.Concat(pr.AssignedReviewers, removedIDs))
+	if err != nil {
+		return err
+	}
+	byUserID := make(map[string]domain.ForgeUser, len(forgeUsers))
+	for _, u := range forgeUsers {
+		byUserID[u.UserID] = u
+	}
+
+	var problems []string
+	reviewers := s.resolve(pr.AssignedReviewers, byUserID, &problems)
+	removed := s.resolve(removedIDs, byUserID, &problems)
+
+	if err := client.SetReviewers(ctx, *link, reviewers, removed); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		s.recordFailure(ctx, link, event.ID, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Syncer) resolve(userIDs []string, byUserID map[string]domain.ForgeUser, problems *[]string) []domain.ForgeUser {
+	var users []domain.ForgeUser
+	for _, id := range userIDs {
+		u, ok := byUserID[id]
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("user %s is not mapped", id))
+			continue
+		}
+		users = append(users, u)
+	}
+	return users
+}
+
+func (s *Syncer) recordFailure(ctx context.Context, link *domain.ForgePullRequest, eventID int64, msg string) {
+	logger.FromContext(ctx).Warn("forge sync failed", "pr_id", link.PullRequestID, "forge", link.Forge, "error", msg)
+
+	err := s.forgeRepo.RecordSyncFailure(ctx, &domain.ForgeSyncFailure{
+		PullRequestID: link.PullRequestID,
+		Forge:         link.Forge,
+		Repository:    link.Repository,
+		Number:        link.Number,
+		EventID:       eventID,
+		Error:         msg,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("forge sync: record failure failed", "pr_id", link.PullRequestID, "error", err)
+	}
+}
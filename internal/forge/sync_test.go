@@ -0,0 +1,210 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// FakeClient запоминает вызовы вместо обращения к API
+type FakeClient struct {
+	mu    sync.Mutex
+	Calls []FakeCall
+	// Err, если задана, возвращается из каждого вызова
+	Err error
+}
+
+type FakeCall struct {
+	PullRequest domain.ForgePullRequest
+	Reviewers   []string
+	Removed     []string
+}
+
+func (c *FakeClient) SetReviewers(_ context.Context, pr domain.ForgePullRequest, reviewers, removed []domain.ForgeUser) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Calls = append(c.Calls, FakeCall{
+		PullRequest: pr,
+		Reviewers:   logins(reviewers),
+		Removed:     logins(removed),
+	})
+	return c.Err
+}
+
+type fakeForgeRepo struct {
+	repository.ForgeRepository
+
+	links    map[string]domain.ForgePullRequest
+	users    map[string]domain.ForgeUser
+	failures []domain.ForgeSyncFailure
+}
+
+func (r *fakeForgeRepo) GetPullRequestByID(_ context.Context, prID string) (*domain.ForgePullRequest, error) {
+	link, ok := r.links[prID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &link, nil
+}
+
+func (r *fakeForgeRepo) GetUsersByUserIDs(_ context.Context, forge domain.Forge, userIDs []string) ([]domain.ForgeUser, error) {
+	var users []domain.ForgeUser
+	for _, id := range userIDs {
+		if u, ok := r.users[id]; ok && u.Forge == forge {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeForgeRepo) RecordSyncFailure(_ context.Context, failure *domain.ForgeSyncFailure) error {
+	r.failures = append(r.failures, *failure)
+	return nil
+}
+
+type fakePrRepo struct {
+	repository.PrRepository
+
+	prs map[string]domain.PullRequest
+}
+
+func (r *fakePrRepo) GetByID(_ context.Context, prID string) (*domain.PullRequest, error) {
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &pr, nil
+}
+
+func newTestSyncer(client *FakeClient) (*Syncer, *fakeForgeRepo) {
+	forgeRepo := &fakeForgeRepo{
+		links: map[string]domain.ForgePullRequest{
+			"pr-1": {Forge: domain.ForgeGitHub, Repository: "acme/app", Number: 42, PullRequestID: "pr-1"},
+		},
+		users: map[string]domain.ForgeUser{
+			"u1": {Forge: domain.ForgeGitHub, Username: "alice", UserID: "u1"},
+			"u2": {Forge: domain.ForgeGitHub, Username: "bob", UserID: "u2"},
+			"u3": {Forge: domain.ForgeGitHub, Username: "carol", UserID: "u3"},
+		},
+	}
+	prRepo := &fakePrRepo{
+		prs: map[string]domain.PullRequest{
+			"pr-1":  {PullRequestID: "pr-1", AssignedReviewers: []string{"u2", "u3"}},
+			"pr-99": {PullRequestID: "pr-99", AssignedReviewers: []string{"u2"}},
+		},
+	}
+	syncer := NewSyncer(forgeRepo, prRepo, map[domain.Forge]Client{domain.ForgeGitHub: client})
+	return syncer, forgeRepo
+}
+
+func event(t *testing.T, id int64, eventType domain.EventType, payload any) domain.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.Event{ID: id, Type: eventType, Payload: data}
+}
+
+func TestSyncerPushesCurrentReviewersOnCreate(t *testing.T) {
+	client := &FakeClient{}
+	syncer, forgeRepo := newTestSyncer(client)
+
+	// В событии устаревший список: берутся текущие ревьюверы PR
+	err := syncer.Handle(context.Background(), event(t, 1, domain.EventPRCreated, domain.PRCreatedPayload{
+		PullRequestID: "pr-1",
+		Reviewers:     []string{"u2"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.Calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(client.Calls))
+	}
+	call := client.Calls[0]
+	if call.PullRequest.Repository != "acme/app" || call.PullRequest.Number != 42 {
+		t.Errorf("pull request = %+v, want acme/app#42", call.PullRequest)
+	}
+	if !slices.Equal(call.Reviewers, []string{"bob", "carol"}) || len(call.Removed) != 0 {
+		t.Errorf("reviewers = %v, removed = %v, want [bob carol] and none", call.Reviewers, call.Removed)
+	}
+	if len(forgeRepo.failures) != 0 {
+		t.Errorf("unexpected failures: %+v", forgeRepo.failures)
+	}
+}
+
+func TestSyncerRemovesOldReviewerOnReassign(t *testing.T) {
+	client := &FakeClient{}
+	syncer, _ := newTestSyncer(client)
+
+	err := syncer.Handle(context.Background(), event(t, 2, domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{
+		PullRequestID: "pr-1",
+		OldReviewerID: "u1",
+		NewReviewerID: "u3",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.Calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(client.Calls))
+	}
+	if call := client.Calls[0]; !slices.Equal(call.Reviewers, []string{"bob", "carol"}) || !slices.Equal(call.Removed, []string{"alice"}) {
+		t.Errorf("reviewers = %v, removed = %v, want [bob carol] and [alice]", call.Reviewers, call.Removed)
+	}
+}
+
+func TestSyncerRecordsFailuresWithoutFailingTheEvent(t *testing.T) {
+	client := &FakeClient{Err: errors.New("github: 422 Unprocessable Entity")}
+	syncer, forgeRepo := newTestSyncer(client)
+	delete(forgeRepo.users, "u3")
+
+	err := syncer.Handle(context.Background(), event(t, 3, domain.EventPRCreated, domain.PRCreatedPayload{PullRequestID: "pr-1"}))
+	if err != nil {
+		t.Fatalf("Handle returned %v, want nil: failures are recorded, not retried", err)
+	}
+
+	// Несопоставленный пользователь пропускается, остальные всё равно передаются
+	if len(client.Calls) != 1 || !slices.Equal(client.Calls[0].Reviewers, []string{"bob"}) {
+		t.Fatalf("calls = %+v, want one call with [bob]", client.Calls)
+	}
+	if len(forgeRepo.failures) != 1 {
+		t.Fatalf("got %d failures, want 1", len(forgeRepo.failures))
+	}
+	failure := forgeRepo.failures[0]
+	if failure.EventID != 3 || failure.PullRequestID != "pr-1" || failure.Number != 42 {
+		t.Errorf("failure = %+v, want event 3 for pr-1 (#42)", failure)
+	}
+	for _, want := range []string{"user u3 is not mapped", "422"} {
+		if !strings.Contains(failure.Error, want) {
+			t.Errorf("failure error %q does not mention %q", failure.Error, want)
+		}
+	}
+}
+
+func TestSyncerSkipsPullRequestsNotFromForge(t *testing.T) {
+	client := &FakeClient{}
+	syncer, forgeRepo := newTestSyncer(client)
+
+	for _, e := range []domain.Event{
+		event(t, 4, domain.EventPRCreated, domain.PRCreatedPayload{PullRequestID: "pr-99"}),
+		event(t, 5, domain.EventPRMerged, domain.PRMergedPayload{PullRequestID: "pr-1"}),
+	} {
+		if err := syncer.Handle(context.Background(), e); err != nil {
+			t.Fatalf("%s: %v", e.Type, err)
+		}
+	}
+
+	if len(client.Calls) != 0 || len(forgeRepo.failures) != 0 {
+		t.Errorf("calls = %+v, failures = %+v, want none", client.Calls, forgeRepo.failures)
+	}
+}
@@ -0,0 +1,110 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func teamToProto(team *domain.Team) *pb.Team {
+	members := make([]*pb.TeamMember, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, &pb.TeamMember{
+			UserId:   m.UserID,
+			Username: m.Username,
+			IsActive: m.IsActive,
+		})
+	}
+	return &pb.Team{
+		TeamName: team.TeamName,
+		Members:  members,
+	}
+}
+
+func teamFromProto(team *pb.Team) *domain.Team {
+	members := make([]domain.TeamMember, 0, len(team.GetMembers()))
+	for _, m := range team.GetMembers() {
+		members = append(members, domain.TeamMember{
+			UserID:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		})
+	}
+	return &domain.Team{
+		TeamName: team.GetTeamName(),
+		Members:  members,
+	}
+}
+
+func userToProto(user *domain.User) *pb.User {
+	return &pb.User{
+		UserId:   user.UserID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+}
+
+func statusToProto(status domain.PRStatus) pb.PullRequestStatus {
+	switch status {
+	case domain.PRStatusOpen:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.PRStatusMerged:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func pullRequestToProto(pr *domain.PullRequest) *pb.PullRequest {
+	return &pb.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		TeamName:          pr.TeamName,
+		Status:            statusToProto(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         timestamp(pr.CreatedAt),
+		MergedAt:          timestamp(pr.MergedAt),
+	}
+}
+
+func pullRequestShortToProto(pr domain.PullRequestShort) *pb.PullRequestShort {
+	return &pb.PullRequestShort{
+		PullRequestId:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorId:        pr.AuthorID,
+		Status:          statusToProto(pr.Status),
+	}
+}
+
+func historyEntryToProto(entry domain.ReviewerHistoryEntry) *pb.ReviewerHistoryEntry {
+	return &pb.ReviewerHistoryEntry{
+		PullRequestId: entry.PullRequestID,
+		Event:         string(entry.Event),
+		UserId:        entry.UserID,
+		RelatedUserId: entry.RelatedUserID,
+		Reason:        entry.Reason,
+		Actor:         entry.Actor,
+		CreatedAt:     timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func eventToProto(event domain.Event) *pb.Event {
+	return &pb.Event{
+		EventId:     event.ID,
+		EventType:   string(event.Type),
+		AggregateId: event.AggregateID,
+		CreatedAt:   timestamppb.New(event.CreatedAt),
+		PayloadJson: string(event.Payload),
+	}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,63 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Домен в ErrorInfo, по нему клиент отличает коды сервиса от чужих
+const errorDomain = "avitopr"
+
+// codeFor сопоставляет доменный код ошибки со статусом gRPC
+func codeFor(code domain.ErrorCode) codes.Code {
+	switch code {
+	case domain.ErrCodeTeamExists, domain.ErrCodePRExists:
+		return codes.AlreadyExists
+	case domain.ErrCodePRMerged, domain.ErrCodeNotAssigned, domain.ErrCodeNoCandidate:
+		return codes.FailedPrecondition
+	case domain.ErrCodeNotFound:
+		return codes.NotFound
+	case domain.ErrCodeBadRequest, domain.ErrCodeIdempotencyMismatch:
+		return codes.InvalidArgument
+	case domain.ErrCodeUnauthorized:
+		return codes.Unauthenticated
+	case domain.ErrCodeForbidden:
+		return codes.PermissionDenied
+	case domain.ErrCodeRateLimited:
+		return codes.ResourceExhausted
+	case domain.ErrCodeRequestInProgress:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus переводит ошибку сервиса в статус gRPC. Доменный код передаётся
+// в ErrorInfo.Reason, текст непредвиденных ошибок наружу не отдаётся.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var errResp *domain.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	st := status.New(codeFor(errResp.ErrorContent.Code), errResp.ErrorContent.Message)
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(errResp.ErrorContent.Code),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,144 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	idempotencyKeyMetadata      = "idempotency-key"
+	idempotentReplayedMetadata  = "idempotent-replayed"
+	maxIdempotencyKeyLength     = 255
+	idempotencyLockTimeout      = time.Minute
+	idempotentResponseMediaType = "application/x-protobuf; proto="
+)
+
+// Изменяющие методы, для которых учитывается idempotency-key, как POST в HTTP API
+var idempotentMethods = map[string]bool{
+	pb.TeamService_CreateTeam_FullMethodName:  true,
+	pb.UserService_SetIsActive_FullMethodName: true,
+	pb.PrService_CreatePR_FullMethodName:      true,
+	pb.PrService_MergePR_FullMethodName:       true,
+	pb.PrService_ReassignPR_FullMethodName:    true,
+}
+
+// idempotencyUnaryInterceptor обрабатывает метаданные idempotency-key так же,
+// как Idempotency-Key в HTTP API: ответ хранится ttl и отдаётся повторно на вызов
+// с тем же ключом и запросом. Сохраняются только успешные ответы, после ошибки
+// ключ освобождается и повтор выполняется заново. Ключи gRPC и HTTP не пересекаются.
+func idempotencyUnaryInterceptor(repo repository.IdempotencyRepository, ttl time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		key := firstMetadata(ctx, idempotencyKeyMetadata)
+		if key == "" || !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency-key is too long")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		record := &domain.IdempotencyRecord{
+			Scope:       "grpc:" + ratelimit.ClientKey(auth.IdentityFromContext(ctx), peerIP(ctx)),
+			Key:         key,
+			RequestHash: grpcRequestHash(info.FullMethod, body),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, err := repo.Acquire(ctx, record, idempotencyLockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return replayResponse(ctx, record, existing)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := repo.Release(context.WithoutCancel(ctx), record.Scope, record.Key); releaseErr != nil {
+				logger.FromContext(ctx).Error("idempotency key release failed", "error", releaseErr)
+			}
+			return nil, err
+		}
+
+		// Сохраняются только успешные ответы, поэтому статус всегда 200
+		respMsg, _ := resp.(proto.Message)
+		respBody, err := proto.Marshal(respMsg)
+		if err == nil {
+			contentType := idempotentResponseMediaType + string(respMsg.ProtoReflect().Descriptor().FullName())
+			err = repo.Complete(context.WithoutCancel(ctx), record.Scope, record.Key, http.StatusOK, contentType, respBody)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("idempotency response save failed", "error", err)
+		}
+		return resp, nil
+	}
+}
+
+func replayResponse(ctx context.Context, record, existing *domain.IdempotencyRecord) (any, error) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		return nil, idempotencyError(domain.ErrCodeIdempotencyMismatch, "idempotency-key was already used with a different request")
+	case existing.StatusCode == 0:
+		return nil, idempotencyError(domain.ErrCodeRequestInProgress, "request with this idempotency-key is still in progress")
+	}
+
+	name, ok := strings.CutPrefix(existing.ContentType, idempotentResponseMediaType)
+	if !ok {
+		// Запись сохранена не этим перехватчиком
+		return nil, idempotencyError(domain.ErrCodeIdempotencyMismatch, "idempotency-key was already used with a different request")
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, err
+	}
+	resp := messageType.New().Interface()
+	if err := proto.Unmarshal(existing.Body, resp); err != nil {
+		return nil, err
+	}
+
+	logger.AddAttrs(ctx, "idempotent_replay", true)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayedMetadata, "true"))
+	return resp, nil
+}
+
+func grpcRequestHash(fullMethod string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte("GRPC " + fullMethod + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func idempotencyError(code domain.ErrorCode, message string) error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	requestIDMetadata  = "x-request-id"
+	maxRequestIDLength = 128
+)
+
+// wrappedStream подменяет контекст серверного стрима
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// requestContext кладёт в контекст идентификатор запроса и логгер вызова
+func requestContext(ctx context.Context, base *slog.Logger, method string) context.Context {
+	requestID := firstMetadata(ctx, requestIDMetadata)
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		requestID = hex.EncodeToString(b)
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+
+	l := base.With(
+		slog.String("request_id", requestID),
+		slog.String("rpc_method", method),
+	)
+	ctx = logger.WithRequestID(ctx, requestID)
+	return logger.WithContext(ctx, l)
+}
+
+// logResult пишет итоговую строку о вызове и переводит ошибку в статус gRPC
+func logResult(ctx context.Context, start time.Time, err error) error {
+	err = toStatus(err)
+	code := status.Code(err)
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	logger.FromContext(ctx).LogAttrs(ctx, level, "rpc completed",
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+	)
+	return err
+}
+
+func loggingUnaryInterceptor(base *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = requestContext(ctx, base, info.FullMethod)
+		resp, err := handler(ctx, req)
+		return resp, logResult(ctx, start, err)
+	}
+}
+
+func loggingStreamInterceptor(base *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := requestContext(ss.Context(), base, info.FullMethod)
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		return logResult(ctx, start, err)
+	}
+}
+
+// authenticate проверяет токен из метаданных authorization: Bearer <token>
+func authenticate(ctx context.Context, authService service.AuthService) (context.Context, *domain.Identity, error) {
+	identity, err := authService.Authenticate(ctx, auth.BearerToken(firstMetadata(ctx, "authorization")))
+	if err != nil {
+		return ctx, nil, err
+	}
+	logger.AddAttrs(ctx, "auth_role", identity.Role, "auth_subject", identity.Subject, "auth_user_id", identity.UserID)
+	return auth.WithIdentity(ctx, identity), identity, nil
+}
+
+// Права те же, что в HTTP API: пользователь читает только свои ревью,
+// остальное доступно администратору. Вызовы с неверным токеном
+// ограничиваются limiter по IP.
+func authUnaryInterceptor(authService service.AuthService, limiter *rateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, identity, err := authenticate(ctx, authService)
+		if err != nil {
+			if limitErr := limiter.allow(ctx, info.FullMethod, ratelimit.ClientKey(nil, peerIP(ctx))); limitErr != nil {
+				return nil, limitErr
+			}
+			return nil, err
+		}
+
+		allowed := identity.IsAdmin()
+		if r, ok := req.(*pb.GetReviewRequest); ok && identity.UserID != "" && identity.UserID == r.GetUserId() {
+			allowed = true
+		}
+		if !allowed {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(authService service.AuthService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, identity, err := authenticate(ss.Context(), authService)
+		if err != nil {
+			return err
+		}
+		if !identity.IsAdmin() {
+			return status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
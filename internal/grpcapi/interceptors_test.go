@@ -0,0 +1,156 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeIdempotencyRepo хранит записи в памяти
+type fakeIdempotencyRepo struct {
+	repository.IdempotencyRepository
+
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func (r *fakeIdempotencyRepo) Acquire(_ context.Context, record *domain.IdempotencyRecord, _ time.Duration) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[record.Scope+"/"+record.Key]; ok {
+		return existing, nil
+	}
+	stored := *record
+	r.records[record.Scope+"/"+record.Key] = &stored
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(_ context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.records[scope+"/"+key]
+	record.StatusCode, record.ContentType, record.Body = statusCode, contentType, body
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Release(_ context.Context, scope, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, scope+"/"+key)
+	return nil
+}
+
+func callerContext(tokenID string, md ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(md...))
+	return auth.WithIdentity(ctx, &domain.Identity{Role: domain.RoleAdmin, Subject: tokenID, TokenID: tokenID})
+}
+
+func createTeamRequest(name string) *pb.CreateTeamRequest {
+	return &pb.CreateTeamRequest{Team: &pb.Team{TeamName: name}}
+}
+
+func TestIdempotencyInterceptorReplaysResponse(t *testing.T) {
+	interceptor := idempotencyUnaryInterceptor(&fakeIdempotencyRepo{records: map[string]*domain.IdempotencyRecord{}}, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.TeamService_CreateTeam_FullMethodName}
+
+	var calls int
+	handler := func(_ context.Context, req any) (any, error) {
+		calls++
+		return &pb.CreateTeamResponse{Team: req.(*pb.CreateTeamRequest).GetTeam()}, nil
+	}
+	ctx := callerContext("a", idempotencyKeyMetadata, "k1")
+
+	first, err := interceptor(ctx, createTeamRequest("backend"), info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := interceptor(ctx, createTeamRequest("backend"), info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if !proto.Equal(first.(proto.Message), second.(proto.Message)) {
+		t.Errorf("replayed %v, want %v", second, first)
+	}
+
+	// Тот же ключ с другим запросом
+	_, err = interceptor(ctx, createTeamRequest("frontend"), info, handler)
+	var errResp *domain.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.ErrorContent.Code != domain.ErrCodeIdempotencyMismatch {
+		t.Errorf("different request with the same key: %v, want %s", err, domain.ErrCodeIdempotencyMismatch)
+	}
+
+	// Ключи разделяются по вызывающим
+	if _, err := interceptor(callerContext("b", idempotencyKeyMetadata, "k1"), createTeamRequest("frontend"), info, handler); err != nil {
+		t.Errorf("same key from another caller: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyInterceptorRetriesAfterError(t *testing.T) {
+	interceptor := idempotencyUnaryInterceptor(&fakeIdempotencyRepo{records: map[string]*domain.IdempotencyRecord{}}, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.TeamService_CreateTeam_FullMethodName}
+
+	var calls int
+	handler := func(_ context.Context, _ any) (any, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("db is down")
+		}
+		return &pb.CreateTeamResponse{}, nil
+	}
+	ctx := callerContext("a", idempotencyKeyMetadata, "k1")
+
+	if _, err := interceptor(ctx, createTeamRequest("backend"), info, handler); err == nil {
+		t.Fatal("first call succeeded, want error")
+	}
+	if _, err := interceptor(ctx, createTeamRequest("backend"), info, handler); err != nil {
+		t.Fatalf("retry after error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	limiter := newRateLimiter(map[string]ratelimit.Limit{"/team": {Rate: 0.001, Burst: 1}})
+	interceptor := rateLimitUnaryInterceptor(limiter)
+	handler := func(context.Context, any) (any, error) { return &pb.GetTeamResponse{}, nil }
+	teamInfo := &grpc.UnaryServerInfo{FullMethod: pb.TeamService_GetTeam_FullMethodName}
+
+	if _, err := interceptor(callerContext("a"), &pb.GetTeamRequest{}, teamInfo, handler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err := interceptor(callerContext("a"), &pb.GetTeamRequest{}, teamInfo, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("over the limit: %v, want ResourceExhausted", err)
+	}
+	if _, err := interceptor(callerContext("b"), &pb.GetTeamRequest{}, teamInfo, handler); err != nil {
+		t.Errorf("another caller: %v", err)
+	}
+
+	// У UserService лимит не задан
+	userInfo := &grpc.UnaryServerInfo{FullMethod: pb.UserService_GetReview_FullMethodName}
+	for range 3 {
+		if _, err := interceptor(callerContext("a"), &pb.GetReviewRequest{}, userInfo, handler); err != nil {
+			t.Fatalf("unlimited service: %v", err)
+		}
+	}
+}
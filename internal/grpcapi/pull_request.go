@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/events"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// События, которые можно получить через StreamEvents
+var streamEventTypes = map[domain.EventType]struct{}{
+	domain.EventPRCreated:          {},
+	domain.EventReviewerAssigned:   {},
+	domain.EventReviewerReassigned: {},
+	domain.EventPRMerged:           {},
+}
+
+type prServer struct {
+	pb.UnimplementedPrServiceServer
+	prService   service.PrService
+	broadcaster *events.Broadcaster
+}
+
+func (s *prServer) CreatePR(ctx context.Context, req *pb.CreatePRRequest) (*pb.CreatePRResponse, error) {
+	pr, err := s.prService.CreatePR(ctx, &domain.PullRequestShort{
+		PullRequestName: req.GetPullRequestName(),
+		AuthorID:        req.GetAuthorId(),
+		Status:          domain.PRStatusOpen,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreatePRResponse{Pr: pullRequestToProto(pr)}, nil
+}
+
+func (s *prServer) MergePR(ctx context.Context, req *pb.MergePRRequest) (*pb.MergePRResponse, error) {
+	pr, err := s.prService.MergePR(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MergePRResponse{Pr: pullRequestToProto(pr)}, nil
+}
+
+func (s *prServer) ReassignPR(ctx context.Context, req *pb.ReassignPRRequest) (*pb.ReassignPRResponse, error) {
+	pr, newReviewerID, err := s.prService.ReassignPR(ctx, req.GetPullRequestId(), req.GetOldUserId(), req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReassignPRResponse{
+		Pr:         pullRequestToProto(pr),
+		ReplacedBy: newReviewerID,
+	}, nil
+}
+
+func (s *prServer) GetHistory(ctx context.Context, req *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	history, err := s.prService.GetHistory(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetHistoryResponse{
+		PullRequestId: req.GetPullRequestId(),
+		History:       make([]*pb.ReviewerHistoryEntry, 0, len(history)),
+	}
+	for _, entry := range history {
+		resp.History = append(resp.History, historyEntryToProto(entry))
+	}
+	return resp, nil
+}
+
+// StreamEvents отправляет события по мере их разбора из outbox. Клиент,
+// не успевающий читать, и все клиенты при остановке сервера отключаются с UNAVAILABLE.
+func (s *prServer) StreamEvents(req *pb.StreamEventsRequest, stream pb.PrService_StreamEventsServer) error {
+	types := streamEventTypes
+	if len(req.GetEventTypes()) > 0 {
+		types = make(map[domain.EventType]struct{}, len(req.GetEventTypes()))
+		for _, t := range req.GetEventTypes() {
+			if _, ok := streamEventTypes[domain.EventType(t)]; !ok {
+				return status.Errorf(codes.InvalidArgument, "unknown event type %s", t)
+			}
+			types[domain.EventType(t)] = struct{}{}
+		}
+	}
+
+	ch, unsubscribe := s.broadcaster.Subscribe()
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream closed, reconnect")
+			}
+			if _, ok := types[event.Type]; !ok {
+				continue
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
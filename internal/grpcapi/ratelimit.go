@@ -0,0 +1,102 @@
+package grpcapi
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Сервисы gRPC ограничиваются лимитами соответствующих групп маршрутов HTTP
+var serviceGroups = map[string]string{
+	pb.TeamService_ServiceDesc.ServiceName: "/team",
+	pb.UserService_ServiceDesc.ServiceName: "/users",
+	pb.PrService_ServiceDesc.ServiceName:   "/pullRequest",
+}
+
+// rateLimiter ограничивает частоту вызовов сервиса теми же RATE_LIMIT_*, что и HTTP.
+// Корзины у gRPC свои, общий бюджет с HTTP API не делится.
+type rateLimiter struct {
+	limiters map[string]*ratelimit.Limiter
+}
+
+func newRateLimiter(limits map[string]ratelimit.Limit) *rateLimiter {
+	limiters := make(map[string]*ratelimit.Limiter, len(limits))
+	for group, limit := range limits {
+		if limit.Enabled() {
+			limiters[group] = ratelimit.New(limit)
+		}
+	}
+	return &rateLimiter{limiters: limiters}
+}
+
+// allow списывает вызов клиента key в лимите сервиса метода.
+// При превышении возвращает RESOURCE_EXHAUSTED с RetryInfo и заголовком retry-after.
+func (l *rateLimiter) allow(ctx context.Context, fullMethod, key string) error {
+	group := serviceGroups[methodService(fullMethod)]
+	limiter, ok := l.limiters[group]
+	if !ok {
+		return nil
+	}
+
+	allowed, retryAfter := limiter.Allow(key)
+	if allowed {
+		return nil
+	}
+	metrics.IncRateLimited(group)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: string(domain.ErrCodeRateLimited), Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// rateLimitUnaryInterceptor ставится после authUnaryInterceptor и считает
+// вызовы по проверенному вызывающему
+func rateLimitUnaryInterceptor(limiter *rateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		key := ratelimit.ClientKey(auth.IdentityFromContext(ctx), peerIP(ctx))
+		if err := limiter.allow(ctx, info.FullMethod, key); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// methodService - имя сервиса из "/avitopr.v1.TeamService/CreateTeam"
+func methodService(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,63 @@
+package grpcapi
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/events"
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"log/slog"
+	"time"
+)
+
+//go:generate protoc -I ../../api/proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative avitopr/v1/avitopr.proto
+
+// Deps - зависимости gRPC-сервера, сервисы собираются из тех же репозиториев, что и HTTP API
+type Deps struct {
+	UserRepo  repository.UserRepository
+	TeamRepo  repository.TeamRepository
+	PrRepo    repository.PrRepository
+	TokenRepo repository.TokenRepository
+
+	AdminTokens []string
+	JWTVerifier service.TokenVerifier
+	// Лимиты частоты по группам маршрутов HTTP, см. serviceGroups
+	RateLimits map[string]ratelimit.Limit
+
+	IdempotencyRepo repository.IdempotencyRepository
+	IdempotencyTTL  time.Duration
+
+	// Источник событий для StreamEvents
+	Broadcaster *events.Broadcaster
+}
+
+// NewServer создаёт gRPC-сервер с TeamService, UserService и PrService
+func NewServer(base *slog.Logger, deps Deps) *grpc.Server {
+	userService := service.NewUserService(deps.UserRepo)
+	teamService := service.NewTeamService(deps.TeamRepo)
+	prService := service.NewPrService(deps.PrRepo)
+	authService := service.NewAuthService(deps.TokenRepo, deps.AdminTokens, deps.JWTVerifier)
+	limiter := newRateLimiter(deps.RateLimits)
+
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			loggingUnaryInterceptor(base),
+			authUnaryInterceptor(authService, limiter),
+			rateLimitUnaryInterceptor(limiter),
+			idempotencyUnaryInterceptor(deps.IdempotencyRepo, deps.IdempotencyTTL),
+		),
+		grpc.ChainStreamInterceptor(
+			loggingStreamInterceptor(base),
+			authStreamInterceptor(authService),
+		),
+	)
+
+	pb.RegisterTeamServiceServer(server, &teamServer{teamService: teamService})
+	pb.RegisterUserServiceServer(server, &userServer{userService: userService})
+	pb.RegisterPrServiceServer(server, &prServer{prService: prService, broadcaster: deps.Broadcaster})
+
+	return server
+}
@@ -0,0 +1,35 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type teamServer struct {
+	pb.UnimplementedTeamServiceServer
+	teamService service.TeamService
+}
+
+func (s *teamServer) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.CreateTeamResponse, error) {
+	if req.GetTeam() == nil {
+		return nil, status.Error(codes.InvalidArgument, "team is required")
+	}
+
+	team, err := s.teamService.CreateTeam(ctx, teamFromProto(req.GetTeam()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateTeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (s *teamServer) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	team, err := s.teamService.GetTeamByName(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTeamResponse{Team: teamToProto(team)}, nil
+}
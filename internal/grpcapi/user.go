@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/grpcapi/pb"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	userService service.UserService
+}
+
+func (s *userServer) SetIsActive(ctx context.Context, req *pb.SetIsActiveRequest) (*pb.SetIsActiveResponse, error) {
+	user, err := s.userService.SetIsActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetIsActiveResponse{User: userToProto(user)}, nil
+}
+
+func (s *userServer) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	prs, err := s.userService.GetUserReviews(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetReviewResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: make([]*pb.PullRequestShort, 0, len(prs)),
+	}
+	for _, pr := range prs {
+		resp.PullRequests = append(resp.PullRequests, pullRequestShortToProto(pr))
+	}
+	return resp, nil
+}
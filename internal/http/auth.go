@@ -0,0 +1,69 @@
+package http
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"slices"
+)
+
+// AuthMiddleware проверяет Bearer-токен и кладёт вызывающего в контекст.
+// Запросы с неверным токеном ограничиваются limiter по IP, чтобы токены
+// нельзя было перебирать без ограничений.
+func AuthMiddleware(authService service.AuthService, limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		identity, err := authService.Authenticate(ctx, auth.BearerToken(c.GetHeader("Authorization")))
+		if err != nil {
+			if !limiter.allow(c, ratelimit.ClientKey(nil, c.ClientIP())) {
+				return
+			}
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		logger.AddAttrs(ctx, "auth_role", identity.Role, "auth_subject", identity.Subject, "auth_user_id", identity.UserID)
+		c.Request = c.Request.WithContext(auth.WithIdentity(ctx, identity))
+		c.Next()
+	}
+}
+
+// RequireRole пропускает только вызывающих с одной из ролей
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := auth.IdentityFromContext(c.Request.Context())
+		if identity == nil || !slices.Contains(roles, identity.Role) {
+			c.Error(forbidden())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrAdmin пропускает админов и пользователя, чей id передан в query-параметре
+func RequireSelfOrAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := auth.IdentityFromContext(c.Request.Context())
+		if !identity.IsAdmin() && (identity == nil || identity.UserID == "" || identity.UserID != c.Query(param)) {
+			c.Error(forbidden())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func forbidden() error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeForbidden,
+			Message: "insufficient permissions",
+		},
+	}
+}
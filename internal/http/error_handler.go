@@ -23,6 +23,18 @@ func HandleError(err error) int {
 			return http.StatusConflict
 		case domain.ErrCodeNotFound:
 			return http.StatusNotFound
+		case domain.ErrCodeBadRequest:
+			return http.StatusBadRequest
+		case domain.ErrCodeUnauthorized:
+			return http.StatusUnauthorized
+		case domain.ErrCodeForbidden:
+			return http.StatusForbidden
+		case domain.ErrCodeRateLimited:
+			return http.StatusTooManyRequests
+		case domain.ErrCodeIdempotencyMismatch:
+			return http.StatusUnprocessableEntity
+		case domain.ErrCodeRequestInProgress:
+			return http.StatusConflict
 		default:
 			return http.StatusInternalServerError
 		}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AuthHandler - обработчик управления токенами доступа
+type AuthHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// CreateToken - POST /auth/tokens/add
+func (h *AuthHandler) CreateToken(c *gin.Context) {
+	var req struct {
+		Name   string      `json:"name"`
+		Role   domain.Role `json:"role"`
+		UserID string      `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token := &domain.APIToken{
+		Name:   req.Name,
+		Role:   req.Role,
+		UserID: req.UserID,
+	}
+
+	created, err := h.authService.CreateToken(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"token": created})
+}
+
+// ListTokens - GET /auth/tokens/list
+func (h *AuthHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.authService.ListTokens(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// RevokeToken - POST /auth/tokens/revoke
+func (h *AuthHandler) RevokeToken(c *gin.Context) {
+	var req struct {
+		TokenID string `json:"token_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.RevokeToken(c.Request.Context(), req.TokenID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token_id": req.TokenID})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// Интервал комментариев-пингов, чтобы прокси не закрывали простаивающее соединение
+	sseHeartbeat = 15 * time.Second
+	// Через сколько миллисекунд браузер переподключается
+	sseRetryMillis = 3000
+)
+
+// EventsHandler - поток событий для дашбордов (Server-Sent Events)
+type EventsHandler struct {
+	eventStreamService service.EventStreamService
+}
+
+func NewEventsHandler(eventStreamService service.EventStreamService) *EventsHandler {
+	return &EventsHandler{
+		eventStreamService: eventStreamService,
+	}
+}
+
+// Stream - GET /events/stream
+func (h *EventsHandler) Stream(c *gin.Context) {
+	filter := service.EventFilter{
+		TeamName: c.Query("team_name"),
+		UserID:   c.Query("user_id"),
+	}
+
+	// Пользовательский токен видит только события о себе
+	identity := auth.IdentityFromContext(c.Request.Context())
+	if !identity.IsAdmin() {
+		if filter.UserID != "" && filter.UserID != identity.UserID {
+			c.Error(&domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeForbidden,
+					Message: "insufficient permissions",
+				},
+			})
+			return
+		}
+		filter.UserID = identity.UserID
+	}
+
+	// EventSource при переподключении присылает Last-Event-ID сам.
+	// id события в потоке - номер доставки, а не id в outbox.
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var afterSeq int64
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		afterSeq = seq
+	}
+
+	ctx := c.Request.Context()
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	w.Flush()
+
+	// Пинги пишутся из той же горутины, что и события
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	events := make(chan domain.Event)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.eventStreamService.Stream(ctx, afterSeq, filter, func(event domain.Event) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.FromContext(ctx).Error("event stream marshal failed", "event_id", event.ID, "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+			w.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		case err := <-done:
+			if err != nil && !errors.Is(err, ctx.Err()) {
+				logger.FromContext(ctx).Warn("event stream closed", "error", err)
+			}
+			return
+		}
+	}
+}
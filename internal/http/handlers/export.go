@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Через сколько строк выгрузка сбрасывается клиенту
+const exportFlushEvery = 500
+
+// ExportHandler - потоковые выгрузки в CSV и NDJSON
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+var (
+	pullRequestExportHeader = []string{"pull_request_id", "pull_request_name", "author_id", "team_name", "status", "assigned_reviewers", "created_at", "merged_at"}
+	assignmentExportHeader  = []string{"pull_request_id", "pull_request_name", "team_name", "status", "reviewer_id", "assigned_at", "verdict", "verdict_at"}
+)
+
+// PullRequests - GET /export/prs
+func (h *ExportHandler) PullRequests(c *gin.Context) {
+	out, ok := newExportWriter(c, "prs", pullRequestExportHeader)
+	if !ok {
+		return
+	}
+
+	err := h.exportService.ExportPullRequests(c.Request.Context(), exportQuery(c), func(pr domain.PullRequestExport) error {
+		return out.write(pr, func() []string {
+			return []string{
+				pr.PullRequestID,
+				pr.PullRequestName,
+				pr.AuthorID,
+				pr.TeamName,
+				string(pr.Status),
+				strings.Join(pr.Reviewers, ";"),
+				formatExportTime(pr.CreatedAt),
+				formatExportTime(pr.MergedAt),
+			}
+		})
+	})
+	out.finish(err)
+}
+
+// Assignments - GET /export/assignments
+func (h *ExportHandler) Assignments(c *gin.Context) {
+	out, ok := newExportWriter(c, "assignments", assignmentExportHeader)
+	if !ok {
+		return
+	}
+
+	err := h.exportService.ExportAssignments(c.Request.Context(), exportQuery(c), func(a domain.AssignmentExport) error {
+		return out.write(a, func() []string {
+			verdict := ""
+			if a.Verdict != nil {
+				verdict = string(*a.Verdict)
+			}
+			return []string{
+				a.PullRequestID,
+				a.PullRequestName,
+				a.TeamName,
+				string(a.Status),
+				a.ReviewerID,
+				formatExportTime(&a.AssignedAt),
+				verdict,
+				formatExportTime(a.VerdictAt),
+			}
+		})
+	})
+	out.finish(err)
+}
+
+func exportQuery(c *gin.Context) service.ExportQuery {
+	return service.ExportQuery{
+		TeamName: c.Query("team_name"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Status:   c.Query("status"),
+	}
+}
+
+// exportWriter пишет строки выгрузки в ответ по мере чтения из базы. Заголовки
+// ответа отправляются с первой строкой, поэтому ошибка фильтров ещё отдаётся как 400.
+type exportWriter struct {
+	c       *gin.Context
+	name    string
+	header  []string
+	ndjson  bool
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+	rows    int
+}
+
+func newExportWriter(c *gin.Context, name string, header []string) (*exportWriter, bool) {
+	w := &exportWriter{c: c, name: name, header: header}
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		w.csv = csv.NewWriter(c.Writer)
+	case "ndjson":
+		w.ndjson = true
+		w.json = json.NewEncoder(c.Writer)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or ndjson"})
+		return nil, false
+	}
+	return w, true
+}
+
+func (w *exportWriter) start() {
+	w.started = true
+
+	ext, contentType := "csv", "text/csv; charset=utf-8"
+	if w.ndjson {
+		ext, contentType = "ndjson", "application/x-ndjson"
+	}
+	filename := fmt.Sprintf("%s-%s.%s", w.name, time.Now().UTC().Format("20060102"), ext)
+
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.c.Header("X-Accel-Buffering", "no")
+	w.c.Status(http.StatusOK)
+
+	if w.csv != nil {
+		_ = w.csv.Write(w.header)
+	}
+}
+
+// write пишет строку; record нужен только для CSV
+func (w *exportWriter) write(value any, record func() []string) error {
+	if !w.started {
+		w.start()
+	}
+
+	if w.csv != nil {
+		row := record()
+		for i := range row {
+			row[i] = csvSafe(row[i])
+		}
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+	} else if err := w.json.Encode(value); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%exportFlushEvery == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// finish завершает выгрузку. Если строки уже отправлены, ошибку можно только
+// записать в лог: клиент получит оборванный файл.
+func (w *exportWriter) finish(err error) {
+	if err != nil && !w.started {
+		w.c.Error(err)
+		return
+	}
+	if err != nil {
+		logger.FromContext(w.c.Request.Context()).Warn("export aborted", "export", w.name, "rows", w.rows, "error", err)
+		return
+	}
+
+	if !w.started {
+		w.start()
+	}
+	_ = w.flush()
+}
+
+// csvSafe экранирует ячейку, которую Excel или LibreOffice выполнили бы как формулу
+// (имя PR или команды вида "=HYPERLINK(...)"): в начало добавляется апостроф.
+// В NDJSON значения отдаются как есть.
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		cell, want string
+	}{
+		{"", ""},
+		{"Add search", "Add search"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+2", "'+1+2"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+		{"2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z"},
+	}
+	for _, tt := range tests {
+		if got := csvSafe(tt.cell); got != tt.want {
+			t.Errorf("csvSafe(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
+
+func TestExportWriterEscapesFormulasOnlyInCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	row := struct {
+		Name string `json:"name"`
+	}{Name: "=cmd|' /C calc'!A0"}
+
+	for format, want := range map[string]string{
+		"csv":    "'=cmd|' /C calc'!A0",
+		"ndjson": `{"name":"=cmd|' /C calc'!A0"}`,
+	} {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/export/prs?format="+format, nil)
+
+		out, ok := newExportWriter(c, "prs", []string{"name"})
+		if !ok {
+			t.Fatalf("%s: writer not created", format)
+		}
+		if err := out.write(row, func() []string { return []string{row.Name} }); err != nil {
+			t.Fatal(err)
+		}
+		out.finish(nil)
+
+		if format == "csv" {
+			records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 2 || records[1][0] != want {
+				t.Errorf("csv rows %q, want cell %q", records, want)
+			}
+			continue
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != want {
+			t.Errorf("ndjson %s, want %s", got, want)
+		}
+	}
+}
@@ -1 +1,34 @@
 package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HealthHandler - проверки живости и готовности для оркестратора
+type HealthHandler struct {
+	healthService service.HealthService
+}
+
+func NewHealthHandler(healthService service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Live - GET /health/live, процесс запущен и обслуживает запросы
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready - GET /health/ready, 503 пока база недоступна или схема отстаёт от бинарника
+func (h *HealthHandler) Ready(c *gin.Context) {
+	readiness := h.healthService.Ready(c.Request.Context())
+	if !readiness.Ready {
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+
+	c.JSON(http.StatusOK, readiness)
+}
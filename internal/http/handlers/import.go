@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Наибольший размер файла импорта
+const maxImportBytes = 10 << 20
+
+// ImportHandler - массовый импорт команд и пользователей
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportTeams - POST /import/teams
+func (h *ImportHandler) ImportTeams(c *gin.Context) {
+	// Формат из параметра format, иначе по Content-Type
+	format := c.Query("format")
+	if format == "" {
+		switch contentType := c.ContentType(); {
+		case strings.Contains(contentType, "csv"):
+			format = service.ImportFormatCSV
+		case strings.Contains(contentType, "yaml"):
+			format = service.ImportFormatYAML
+		}
+	}
+
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.importService.ImportTeams(c.Request.Context(), format, data, dryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/forge"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+// Ограничение размера тела входящего вебхука
+const maxForgePayload = 5 << 20
+
+// IntegrationHandler - обработчик входящих вебхуков GitHub/GitLab
+type IntegrationHandler struct {
+	forgeService service.ForgeService
+	githubSecret string
+	gitlabToken  string
+}
+
+func NewIntegrationHandler(forgeService service.ForgeService, githubSecret, gitlabToken string) *IntegrationHandler {
+	return &IntegrationHandler{
+		forgeService: forgeService,
+		githubSecret: githubSecret,
+		gitlabToken:  gitlabToken,
+	}
+}
+
+// GitHub - POST /integrations/github
+func (h *IntegrationHandler) GitHub(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxForgePayload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !forge.VerifyGitHubSignature(h.githubSecret, c.GetHeader(forge.GitHubSignatureHeader), body) {
+		c.Error(unauthorized("invalid webhook signature"))
+		return
+	}
+
+	event, err := forge.ParseGitHubEvent(c.GetHeader(forge.GitHubEventHeader), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.handleEvent(c, event)
+}
+
+// GitLab - POST /integrations/gitlab
+func (h *IntegrationHandler) GitLab(c *gin.Context) {
+	if !forge.VerifyGitLabToken(h.gitlabToken, c.GetHeader(forge.GitLabTokenHeader)) {
+		c.Error(unauthorized("invalid webhook token"))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxForgePayload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := forge.ParseGitLabEvent(c.GetHeader(forge.GitLabEventHeader), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.handleEvent(c, event)
+}
+
+func (h *IntegrationHandler) handleEvent(c *gin.Context, event *domain.ForgePREvent) {
+	if event == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+
+	link, err := h.forgeService.HandlePREvent(c.Request.Context(), event)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if link == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "processed",
+		"pull_request": link,
+	})
+}
+
+// MapUser - POST /integrations/users/map
+func (h *IntegrationHandler) MapUser(c *gin.Context) {
+	var req domain.ForgeUser
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.forgeService.MapUser(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"forge_user": user})
+}
+
+// ListUsers - GET /integrations/users/list
+func (h *IntegrationHandler) ListUsers(c *gin.Context) {
+	users, err := h.forgeService.ListUsers(c.Request.Context(), domain.Forge(c.Query("forge")))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"forge_users": users})
+}
+
+// ListSyncFailures - GET /integrations/syncFailures
+func (h *IntegrationHandler) ListSyncFailures(c *gin.Context) {
+	failures, err := h.forgeService.ListSyncFailures(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"failures": failures})
+}
+
+func unauthorized(message string) error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeUnauthorized,
+			Message: message,
+		},
+	}
+}
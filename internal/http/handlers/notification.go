@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// NotificationHandler - каналы уведомлений пользователей и журнал доставки
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListPreferences - GET /notifications/preferences
+func (h *NotificationHandler) ListPreferences(c *gin.Context) {
+	userID := c.Query("user_id")
+
+	prefs, err := h.notificationService.ListPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":     userID,
+		"preferences": prefs,
+	})
+}
+
+// SetPreference - POST /notifications/preferences/set
+func (h *NotificationHandler) SetPreference(c *gin.Context) {
+	var req struct {
+		UserID  string                     `json:"user_id"`
+		Channel domain.NotificationChannel `json:"channel"`
+		Address string                     `json:"address"`
+		// По умолчанию канал включён
+		Enabled *bool                     `json:"enabled"`
+		Kinds   []domain.NotificationKind `json:"kinds"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !allowSelfOrAdmin(c, req.UserID) {
+		return
+	}
+
+	pref := &domain.NotificationPreference{
+		UserID:  req.UserID,
+		Channel: req.Channel,
+		Address: req.Address,
+		Enabled: req.Enabled == nil || *req.Enabled,
+		Kinds:   req.Kinds,
+	}
+
+	saved, err := h.notificationService.SetPreference(c.Request.Context(), pref)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preference": saved})
+}
+
+// DeletePreference - POST /notifications/preferences/delete
+func (h *NotificationHandler) DeletePreference(c *gin.Context) {
+	var req struct {
+		UserID  string                     `json:"user_id"`
+		Channel domain.NotificationChannel `json:"channel"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !allowSelfOrAdmin(c, req.UserID) {
+		return
+	}
+
+	if err := h.notificationService.DeletePreference(c.Request.Context(), req.UserID, req.Channel); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": req.UserID,
+		"channel": req.Channel,
+	})
+}
+
+// ListDeliveries - GET /notifications/deliveries
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+	}
+
+	deliveries, err := h.notificationService.ListDeliveries(
+		c.Request.Context(),
+		c.Query("user_id"),
+		domain.NotificationStatus(c.Query("status")),
+		limit,
+	)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// GetDigest - GET /notifications/digest
+func (h *NotificationHandler) GetDigest(c *gin.Context) {
+	digest, err := h.notificationService.GetDigest(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"digest": digest})
+}
+
+// SetDigest - POST /notifications/digest/set
+func (h *NotificationHandler) SetDigest(c *gin.Context) {
+	var req struct {
+		UserID string `json:"user_id"`
+		// По умолчанию сводка включена
+		Enabled  *bool  `json:"enabled"`
+		SendAt   string `json:"send_at"`
+		Timezone string `json:"timezone"`
+		Days     []int  `json:"days"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !allowSelfOrAdmin(c, req.UserID) {
+		return
+	}
+
+	digest, err := h.notificationService.SetDigest(c.Request.Context(), &domain.DigestSubscription{
+		UserID:   req.UserID,
+		Enabled:  req.Enabled == nil || *req.Enabled,
+		SendAt:   req.SendAt,
+		Timezone: req.Timezone,
+		Days:     req.Days,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"digest": digest})
+}
+
+// DeleteDigest - POST /notifications/digest/delete
+func (h *NotificationHandler) DeleteDigest(c *gin.Context) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !allowSelfOrAdmin(c, req.UserID) {
+		return
+	}
+
+	if err := h.notificationService.DeleteDigest(c.Request.Context(), req.UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": req.UserID})
+}
+
+// allowSelfOrAdmin - пользовательский токен меняет только свои каналы
+func allowSelfOrAdmin(c *gin.Context, userID string) bool {
+	identity := auth.IdentityFromContext(c.Request.Context())
+	if !identity.IsAdmin() && (identity == nil || identity.UserID == "" || identity.UserID != userID) {
+		c.Error(&domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodeForbidden,
+				Message: "insufficient permissions",
+			},
+		})
+		return false
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/Unitazavr/AvitoPR/internal/auth"
 	"github.com/Unitazavr/AvitoPR/internal/domain"
 	"github.com/Unitazavr/AvitoPR/internal/service"
 	"github.com/gin-gonic/gin"
@@ -71,6 +72,7 @@ func (h *PrHandler) ReassignPR(c *gin.Context) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
 		OldUserID     string `json:"old_reviewer_id"`
+		Reason        string `json:"reason"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -78,7 +80,7 @@ func (h *PrHandler) ReassignPR(c *gin.Context) {
 		return
 	}
 
-	updatedPR, replacedBy, err := h.prService.ReassignPR(c.Request.Context(), req.PullRequestID, req.OldUserID)
+	updatedPR, replacedBy, err := h.prService.ReassignPR(c.Request.Context(), req.PullRequestID, req.OldUserID, req.Reason)
 	if err != nil {
 		c.Error(err)
 		return
@@ -89,3 +91,53 @@ func (h *PrHandler) ReassignPR(c *gin.Context) {
 		"replaced_by": replacedBy,
 	})
 }
+
+// SubmitVerdict - POST /pullRequest/review
+func (h *PrHandler) SubmitVerdict(c *gin.Context) {
+	var req struct {
+		PullRequestID string               `json:"pull_request_id"`
+		UserID        string               `json:"user_id"`
+		Verdict       domain.ReviewVerdict `json:"verdict"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Пользовательский токен выносит вердикт только от своего имени
+	identity := auth.IdentityFromContext(c.Request.Context())
+	if !identity.IsAdmin() && (identity == nil || identity.UserID == "" || identity.UserID != req.UserID) {
+		c.Error(&domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodeForbidden,
+				Message: "insufficient permissions",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.SubmitVerdict(c.Request.Context(), req.PullRequestID, req.UserID, req.Verdict)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// GetHistory - GET /pullRequest/history
+func (h *PrHandler) GetHistory(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+
+	history, err := h.prService.GetHistory(c.Request.Context(), prID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pull_request_id": prID,
+		"history":         history,
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ReminderHandler - состояние и настройка напоминаний о зависших PR
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+func NewReminderHandler(reminderService service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// Status - GET /reminders/status
+func (h *ReminderHandler) Status(c *gin.Context) {
+	status, err := h.reminderService.Status(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetTeamThreshold - POST /reminders/setTeamThreshold
+func (h *ReminderHandler) SetTeamThreshold(c *gin.Context) {
+	var req struct {
+		TeamName         string `json:"team_name"`
+		ThresholdMinutes *int   `json:"threshold_minutes"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	threshold, err := h.reminderService.SetTeamThreshold(c.Request.Context(), req.TeamName, req.ThresholdMinutes)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": threshold})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ScheduleHandler - рабочие часы и праздники команд и пользователей
+type ScheduleHandler struct {
+	scheduleService service.ScheduleService
+}
+
+func NewScheduleHandler(scheduleService service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// GetTeamCalendar - GET /team/calendar
+func (h *ScheduleHandler) GetTeamCalendar(c *gin.Context) {
+	calendar, err := h.scheduleService.GetTeamCalendar(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// SetTeamSchedule - POST /team/setSchedule
+func (h *ScheduleHandler) SetTeamSchedule(c *gin.Context) {
+	var req struct {
+		TeamName string               `json:"team_name"`
+		Schedule *domain.WorkSchedule `json:"schedule"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.scheduleService.SetTeamSchedule(c.Request.Context(), req.TeamName, req.Schedule)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// SetTeamHolidays - POST /team/setHolidays
+func (h *ScheduleHandler) SetTeamHolidays(c *gin.Context) {
+	var req struct {
+		TeamName string           `json:"team_name"`
+		Holidays []domain.Holiday `json:"holidays"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.scheduleService.SetTeamHolidays(c.Request.Context(), req.TeamName, req.Holidays)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// SetUserSchedule - POST /users/setSchedule
+func (h *ScheduleHandler) SetUserSchedule(c *gin.Context) {
+	var req struct {
+		UserID   string               `json:"user_id"`
+		Schedule *domain.WorkSchedule `json:"schedule"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.scheduleService.SetUserSchedule(c.Request.Context(), req.UserID, req.Schedule)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": req.UserID, "schedule": schedule})
+}
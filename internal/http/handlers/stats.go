@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// StatsHandler - аналитика скорости ревью
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// Turnaround - GET /stats/turnaround
+func (h *StatsHandler) Turnaround(c *gin.Context) {
+	stats, err := h.statsService.Turnaround(c.Request.Context(), c.Query("from"), c.Query("to"), c.Query("team_name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
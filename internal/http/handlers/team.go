@@ -21,8 +21,9 @@ func NewTeamHandler(teamService service.TeamService) *TeamHandler {
 // CreateTeam - POST /team/add
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	var req struct {
-		TeamName string              `json:"team_name"`
-		Members  []domain.TeamMember `json:"members"`
+		TeamName         string              `json:"team_name"`
+		Members          []domain.TeamMember `json:"members"`
+		ReviewSLAMinutes *int                `json:"review_sla_minutes"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,8 +32,9 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	}
 
 	team := &domain.Team{
-		TeamName: req.TeamName,
-		Members:  req.Members,
+		TeamName:         req.TeamName,
+		Members:          req.Members,
+		ReviewSLAMinutes: req.ReviewSLAMinutes,
 	}
 
 	createdTeam, err := h.teamService.CreateTeam(c.Request.Context(), team)
@@ -57,3 +59,24 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+// SetReviewSLA - POST /team/setReviewSLA
+func (h *TeamHandler) SetReviewSLA(c *gin.Context) {
+	var req struct {
+		TeamName         string `json:"team_name"`
+		ReviewSLAMinutes *int   `json:"review_sla_minutes"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.teamService.SetReviewSLA(c.Request.Context(), req.TeamName, req.ReviewSLAMinutes)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": team})
+}
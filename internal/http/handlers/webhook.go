@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// WebhookHandler - обработчик управления исходящими вебхуками
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook - POST /webhooks/add
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req struct {
+		URL        string             `json:"url"`
+		EventTypes []domain.EventType `json:"event_types"`
+		Secret     string             `json:"secret"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := &domain.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	}
+
+	created, err := h.webhookService.CreateWebhook(c.Request.Context(), sub)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": created})
+}
+
+// ListWebhooks - GET /webhooks/list
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// DeleteWebhook - POST /webhooks/delete
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	var req struct {
+		WebhookID string `json:"webhook_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), req.WebhookID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhook_id": req.WebhookID})
+}
+
+// ListDeadLetters - GET /webhooks/deadLetters
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	webhookID := c.Query("webhook_id")
+
+	deliveries, err := h.webhookService.ListDeadLetters(c.Request.Context(), webhookID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// Redeliver - POST /webhooks/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	var req struct {
+		DeliveryID int64 `json:"delivery_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), req.DeliveryID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
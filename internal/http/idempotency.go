@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// Тело запроса с ключом читается в память целиком. Предел не меньше,
+	// чем у самого большого эндпоинта (импорт, 10 МиБ), больше - 413.
+	maxIdempotentRequestBytes = 10 << 20
+	// Через сколько незавершённый запрос считается брошенным
+	idempotencyLockTimeout = time.Minute
+)
+
+// bodyRecorder копирует тело ответа, чтобы сохранить его для повторов
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware обрабатывает заголовок Idempotency-Key у POST-запросов:
+// первый ответ сохраняется на ttl и отдаётся повторно на запросы с тем же ключом
+// и телом. Ключ с другим телом отклоняется. Ключи разделяются по клиентам.
+func IdempotencyMiddleware(repo repository.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.Error(idempotencyError(domain.ErrCodeBadRequest, "Idempotency-Key is too long"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record := &domain.IdempotencyRecord{
+			Scope:       clientKey(c),
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.FullPath(), c.Request.URL.Query(), body),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, err := repo.Acquire(ctx, record, idempotencyLockTimeout)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			replay(c, record, existing)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Ошибки сервиса и лимиты не сохраняем: повтор должен выполниться заново
+		status := recorder.Status()
+		if len(c.Errors) > 0 || !recorder.Written() || status >= 500 || status == http.StatusTooManyRequests {
+			if err := repo.Release(context.WithoutCancel(ctx), record.Scope, record.Key); err != nil {
+				logger.FromContext(ctx).Error("idempotency key release failed", "error", err)
+			}
+			return
+		}
+
+		err = repo.Complete(context.WithoutCancel(ctx), record.Scope, record.Key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		if err != nil {
+			logger.FromContext(ctx).Error("idempotency response save failed", "error", err)
+		}
+	}
+}
+
+func replay(c *gin.Context, record, existing *domain.IdempotencyRecord) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		c.Error(idempotencyError(domain.ErrCodeIdempotencyMismatch, "Idempotency-Key was already used with a different request"))
+		c.Abort()
+	case existing.StatusCode == 0:
+		c.Error(idempotencyError(domain.ErrCodeRequestInProgress, "request with this Idempotency-Key is still in progress"))
+		c.Abort()
+	default:
+		logger.AddAttrs(c.Request.Context(), "idempotent_replay", true)
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+		c.Abort()
+	}
+}
+
+// requestHash учитывает query-параметры (например, dry_run импорта)
+// в каноническом порядке, чтобы перестановка параметров не меняла хэш
+func requestHash(method, route string, query url.Values, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + route + "?" + query.Encode() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func idempotencyError(code domain.ErrorCode, message string) error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
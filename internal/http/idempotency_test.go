@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyRepo хранит записи в памяти
+type fakeIdempotencyRepo struct {
+	repository.IdempotencyRepository
+
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func (r *fakeIdempotencyRepo) Acquire(_ context.Context, record *domain.IdempotencyRecord, _ time.Duration) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[record.Scope+"/"+record.Key]; ok {
+		return existing, nil
+	}
+	stored := *record
+	r.records[record.Scope+"/"+record.Key] = &stored
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(_ context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.records[scope+"/"+key]
+	record.StatusCode, record.ContentType, record.Body = statusCode, contentType, body
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Release(_ context.Context, scope, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, scope+"/"+key)
+	return nil
+}
+
+func newIdempotentRouter(calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.POST("/import/teams", IdempotencyMiddleware(&fakeIdempotencyRepo{records: map[string]*domain.IdempotencyRecord{}}, time.Hour), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusOK, gin.H{"dry_run": c.Query("dry_run")})
+	})
+	return router
+}
+
+func post(router *gin.Engine, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyRejectsOversizedBody(t *testing.T) {
+	var calls int
+	router := newIdempotentRouter(&calls)
+
+	w := post(router, "/import/teams", "k1", strings.Repeat("x", maxIdempotentRequestBytes+1))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+	if calls != 0 {
+		t.Errorf("handler called %d times with a truncated body", calls)
+	}
+}
+
+func TestIdempotencyKeyCoversQuery(t *testing.T) {
+	var calls int
+	router := newIdempotentRouter(&calls)
+
+	if w := post(router, "/import/teams?dry_run=true&format=csv", "k1", "a,b"); w.Code != http.StatusOK {
+		t.Fatalf("first request: %d", w.Code)
+	}
+
+	// Тот же запрос с другим порядком параметров - повтор сохранённого ответа
+	w := post(router, "/import/teams?format=csv&dry_run=true", "k1", "a,b")
+	if w.Code != http.StatusOK || w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("replay: status %d, replayed %q", w.Code, w.Header().Get(IdempotentReplayedHeader))
+	}
+
+	// Тот же ключ и тело, но dry_run=false - это другой запрос
+	if w := post(router, "/import/teams?dry_run=false&format=csv", "k1", "a,b"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different query with the same key: %d, want 422", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Максимальная длина принимаемого от клиента X-Request-ID
+const maxRequestIDLength = 128
+
+// LoggingMiddleware присваивает запросу идентификатор (из X-Request-ID или новый),
+// кладёт логгер запроса в контекст и пишет итоговую строку с задержкой и статусом
+func LoggingMiddleware(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		l := base.With(
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+		)
+		// Связываем логи с трейсом, если span открыт otelgin
+		if span := trace.SpanFromContext(c.Request.Context()); span.SpanContext().IsValid() {
+			span.SetAttributes(attribute.String("request_id", requestID))
+			l = l.With(slog.String("trace_id", span.SpanContext().TraceID().String()))
+		}
+		ctx := logger.WithRequestID(c.Request.Context(), requestID)
+		ctx = logger.WithContext(ctx, l)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		logger.FromContext(ctx).LogAttrs(ctx, level, "request completed",
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter ограничивает частоту запросов к группе маршрутов
+// (первый сегмент пути, например "/pullRequest")
+type RateLimiter struct {
+	limiters map[string]*ratelimit.Limiter
+}
+
+func NewRateLimiter(limits map[string]ratelimit.Limit) *RateLimiter {
+	limiters := make(map[string]*ratelimit.Limiter, len(limits))
+	for group, limit := range limits {
+		if limit.Enabled() {
+			limiters[group] = ratelimit.New(limit)
+		}
+	}
+	return &RateLimiter{limiters: limiters}
+}
+
+// RateLimitMiddleware ограничивает клиента по вызывающему из контекста, поэтому
+// для защищённых маршрутов ставится после AuthMiddleware. На открытых маршрутах
+// клиент определяется по IP.
+func RateLimitMiddleware(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.allow(c, clientKey(c)) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow списывает запрос клиента key в лимите группы маршрута.
+// При превышении отвечает 429 и прерывает цепочку.
+func (l *RateLimiter) allow(c *gin.Context, key string) bool {
+	if l == nil {
+		return true
+	}
+	group := routeGroup(c.FullPath())
+	limiter, ok := l.limiters[group]
+	if !ok {
+		return true
+	}
+
+	allowed, retryAfter := limiter.Allow(key)
+	if !allowed {
+		metrics.IncRateLimited(group)
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.Error(&domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodeRateLimited,
+				Message: "rate limit exceeded",
+			},
+		})
+		c.Abort()
+	}
+	return allowed
+}
+
+// routeGroup - первый сегмент шаблона маршрута: "/team/add" -> "/team"
+func routeGroup(route string) string {
+	if route == "" {
+		return ""
+	}
+	if i := strings.IndexByte(route[1:], '/'); i >= 0 {
+		return route[:i+1]
+	}
+	return route
+}
+
+// clientKey - проверенный вызывающий из контекста, без него - IP
+func clientKey(c *gin.Context) string {
+	return ratelimit.ClientKey(auth.IdentityFromContext(c.Request.Context()), c.ClientIP())
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
+	"github.com/Unitazavr/AvitoPR/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAuth принимает только токены из tokens
+type fakeAuth struct {
+	service.AuthService
+
+	tokens map[string]*domain.Identity
+}
+
+func (a *fakeAuth) Authenticate(_ context.Context, token string) (*domain.Identity, error) {
+	if identity, ok := a.tokens[token]; ok {
+		return identity, nil
+	}
+	return nil, &domain.ErrorResponse{ErrorContent: domain.ErrorBody{Code: domain.ErrCodeUnauthorized, Message: "invalid token"}}
+}
+
+func newRateLimitedRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	authService := &fakeAuth{tokens: map[string]*domain.Identity{
+		"token-a": {Role: domain.RoleAdmin, Subject: "a", TokenID: "a"},
+		"token-b": {Role: domain.RoleAdmin, Subject: "b", TokenID: "b"},
+	}}
+	limiter := NewRateLimiter(map[string]ratelimit.Limit{
+		"/team":   {Rate: 0.001, Burst: 2},
+		"/health": {Rate: 0.001, Burst: 2},
+	})
+
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET("/health/live", RateLimitMiddleware(limiter), func(c *gin.Context) { c.Status(http.StatusOK) })
+	authenticated := router.Group("", AuthMiddleware(authService, limiter), RateLimitMiddleware(limiter))
+	authenticated.GET("/team/get", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func get(router *gin.Engine, path, token string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "192.0.2.1:4242"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitInvalidTokensAreLimitedByIP(t *testing.T) {
+	router := newRateLimitedRouter(t)
+
+	// Каждый запрос с новым токеном: раньше это был новый клиент со своей корзиной
+	codes := []int{
+		get(router, "/team/get", "random-1"),
+		get(router, "/team/get", "random-2"),
+		get(router, "/team/get", "random-3"),
+	}
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Fatalf("responses = %v, want %v", codes, want)
+		}
+	}
+
+	// Проверенный токен с того же IP считается отдельно
+	if code := get(router, "/team/get", "token-a"); code != http.StatusOK {
+		t.Errorf("valid token after invalid ones: %d, want 200", code)
+	}
+}
+
+func TestRateLimitKeysOnIdentity(t *testing.T) {
+	router := newRateLimitedRouter(t)
+
+	for range 2 {
+		if code := get(router, "/team/get", "token-a"); code != http.StatusOK {
+			t.Fatalf("token-a within burst: %d, want 200", code)
+		}
+	}
+	if code := get(router, "/team/get", "token-a"); code != http.StatusTooManyRequests {
+		t.Errorf("token-a over burst: %d, want 429", code)
+	}
+	if code := get(router, "/team/get", "token-b"); code != http.StatusOK {
+		t.Errorf("token-b from the same IP: %d, want 200", code)
+	}
+}
+
+func TestRateLimitPublicRoutesIgnoreBearer(t *testing.T) {
+	router := newRateLimitedRouter(t)
+
+	codes := []int{
+		get(router, "/health/live", "random-1"),
+		get(router, "/health/live", "random-2"),
+		get(router, "/health/live", "random-3"),
+	}
+	if codes[2] != http.StatusTooManyRequests {
+		t.Errorf("responses = %v, want the third limited by IP", codes)
+	}
+}
@@ -3,38 +3,191 @@ package http
 import (
 	"errors"
 	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/events"
 	"github.com/Unitazavr/AvitoPR/internal/http/handlers"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/notify"
+	"github.com/Unitazavr/AvitoPR/internal/ratelimit"
 	"github.com/Unitazavr/AvitoPR/internal/repository"
 	"github.com/Unitazavr/AvitoPR/internal/service"
 	"github.com/gin-gonic/gin"
+	"time"
 )
 
-func RegisterRoutes(router *gin.Engine, userRepo repository.UserRepository, teamRepo repository.TeamRepository, prRepo repository.PrRepository) {
-	userService := service.NewUserService(userRepo)
-	teamService := service.NewTeamService(teamRepo)
-	prService := service.NewPrService(prRepo)
+// Deps - зависимости, из которых собираются сервисы и контроллеры
+type Deps struct {
+	UserRepo    repository.UserRepository
+	TeamRepo    repository.TeamRepository
+	PrRepo      repository.PrRepository
+	WebhookRepo repository.WebhookRepository
+	ForgeRepo   repository.ForgeRepository
+	TokenRepo   repository.TokenRepository
+	OutboxRepo  repository.OutboxRepository
+
+	// Источник событий для /events/stream
+	Broadcaster *events.Broadcaster
+
+	ReminderRepo repository.ReminderRepository
+	ScheduleRepo repository.ScheduleRepository
+	// Каналы уведомлений пользователей и журнал доставки
+	NotificationRepo repository.NotificationRepository
+	// Подписки на ежедневную сводку ревью
+	DigestRepo repository.DigestRepository
+	// Хосты, на которые пользователи могут указать вебхуки чатов
+	ChatWebhookHosts notify.ChatHosts
+
+	StatsRepo repository.StatsRepository
+	// Сколько кэшируется ответ /stats/turnaround, 0 - без кэша
+	StatsCacheTTL time.Duration
+
+	ExportRepo repository.ExportRepository
+	ImportRepo repository.ImportRepository
+	// Планировщик напоминаний, nil - напоминания выключены
+	ReminderScheduler service.ReminderScheduler
+
+	// Версия схемы в базе для /health/ready и версия, которую ожидает бинарник
+	SchemaRepo    repository.SchemaRepository
+	SchemaVersion uint
+
+	IdempotencyRepo repository.IdempotencyRepository
+	// Сколько хранится ответ на запрос с Idempotency-Key
+	IdempotencyTTL time.Duration
+
+	// Админские токены из конфигурации, в дополнение к токенам в базе
+	AdminTokens []string
+	// Проверка JWT шлюза, nil - JWT не принимаются
+	JWTVerifier service.TokenVerifier
+	// Лимиты частоты запросов по группам маршрутов ("/team", "/users", "/pullRequest")
+	RateLimits map[string]ratelimit.Limit
+
+	// Секреты входящих вебхуков GitHub/GitLab
+	GitHubWebhookSecret string
+	GitLabWebhookToken  string
+}
+
+func RegisterRoutes(router *gin.Engine, deps Deps) {
+	userService := service.NewUserService(deps.UserRepo)
+	teamService := service.NewTeamService(deps.TeamRepo)
+	prService := service.NewPrService(deps.PrRepo)
+	webhookService := service.NewWebhookService(deps.WebhookRepo)
+	forgeService := service.NewForgeService(deps.ForgeRepo, prService)
+	authService := service.NewAuthService(deps.TokenRepo, deps.AdminTokens, deps.JWTVerifier)
+	eventStreamService := service.NewEventStreamService(deps.OutboxRepo, deps.Broadcaster)
+	reminderService := service.NewReminderService(deps.ReminderRepo, deps.ReminderScheduler)
+	scheduleService := service.NewScheduleService(deps.ScheduleRepo)
+	notificationService := service.NewNotificationService(deps.NotificationRepo, deps.DigestRepo, deps.ChatWebhookHosts)
+	statsService := service.NewStatsService(deps.StatsRepo, deps.ScheduleRepo, deps.StatsCacheTTL)
+	exportService := service.NewExportService(deps.ExportRepo)
+	importService := service.NewImportService(deps.ImportRepo)
+	healthService := service.NewHealthService(deps.SchemaRepo, deps.SchemaVersion)
 
 	userHandler := handlers.NewUserHandler(userService)
 	teamHandler := handlers.NewTeamHandler(teamService)
 	prHandler := handlers.NewPrHandler(prService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	integrationHandler := handlers.NewIntegrationHandler(forgeService, deps.GitHubWebhookSecret, deps.GitLabWebhookToken)
+	authHandler := handlers.NewAuthHandler(authService)
+	eventsHandler := handlers.NewEventsHandler(eventStreamService)
+	reminderHandler := handlers.NewReminderHandler(reminderService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	importHandler := handlers.NewImportHandler(importService)
+	healthHandler := handlers.NewHealthHandler(healthService)
 
 	router.Use(ErrorMiddleware())
 
-	teamGroup := router.Group("/team")
+	// Открытые маршруты ограничиваются по IP, защищённые - по вызывающему
+	rateLimiter := NewRateLimiter(deps.RateLimits)
+	public := router.Group("", RateLimitMiddleware(rateLimiter))
+
+	public.GET("/metrics", gin.WrapH(metrics.Handler()))
+	public.GET("/health/live", healthHandler.Live)
+	public.GET("/health/ready", healthHandler.Ready)
+
+	// Входящие вебхуки проверяются своими подписями, а не токенами API
+	public.POST("/integrations/github", integrationHandler.GitHub)
+	public.POST("/integrations/gitlab", integrationHandler.GitLab)
+
+	authenticated := router.Group("", AuthMiddleware(authService, rateLimiter), RateLimitMiddleware(rateLimiter))
+	idempotent := authenticated.Group("", IdempotencyMiddleware(deps.IdempotencyRepo, deps.IdempotencyTTL))
+	admin := idempotent.Group("", RequireRole(domain.RoleAdmin))
+
+	// Пользовательский токен может читать только свои ревью
+	idempotent.GET("/users/getReview", RequireSelfOrAdmin("user_id"), userHandler.GetUserReviews)
+	// Пользователь выносит вердикт только от своего имени, проверяется в обработчике
+	idempotent.POST("/pullRequest/review", prHandler.SubmitVerdict)
+	// Пользователь получает только события о себе, фильтр проверяется в обработчике
+	authenticated.GET("/events/stream", eventsHandler.Stream)
+	// Пользователь настраивает только свои каналы уведомлений и сводку, для изменений проверяется в обработчике
+	idempotent.GET("/notifications/preferences", RequireSelfOrAdmin("user_id"), notificationHandler.ListPreferences)
+	idempotent.POST("/notifications/preferences/set", notificationHandler.SetPreference)
+	idempotent.POST("/notifications/preferences/delete", notificationHandler.DeletePreference)
+	idempotent.GET("/notifications/digest", RequireSelfOrAdmin("user_id"), notificationHandler.GetDigest)
+	idempotent.POST("/notifications/digest/set", notificationHandler.SetDigest)
+	idempotent.POST("/notifications/digest/delete", notificationHandler.DeleteDigest)
+
+	teamGroup := admin.Group("/team")
 	{
 		teamGroup.POST("/add", teamHandler.CreateTeam)
 		teamGroup.GET("/get", teamHandler.GetTeam)
+		teamGroup.POST("/setReviewSLA", teamHandler.SetReviewSLA)
+		teamGroup.GET("/calendar", scheduleHandler.GetTeamCalendar)
+		teamGroup.POST("/setSchedule", scheduleHandler.SetTeamSchedule)
+		teamGroup.POST("/setHolidays", scheduleHandler.SetTeamHolidays)
+	}
+
+	admin.POST("/users/setIsActive", userHandler.SetIsActive)
+	admin.POST("/users/setSchedule", scheduleHandler.SetUserSchedule)
+
+	admin.POST("/pullRequest/create", prHandler.CreatePR)
+	admin.POST("/pullRequest/merge", prHandler.MergePR)
+	admin.POST("/pullRequest/reassign", prHandler.ReassignPR)
+	admin.GET("/pullRequest/history", prHandler.GetHistory)
+
+	webhooksGroup := admin.Group("/webhooks")
+	{
+		webhooksGroup.POST("/add", webhookHandler.CreateWebhook)
+		webhooksGroup.GET("/list", webhookHandler.ListWebhooks)
+		webhooksGroup.POST("/delete", webhookHandler.DeleteWebhook)
+		webhooksGroup.GET("/deadLetters", webhookHandler.ListDeadLetters)
+		webhooksGroup.POST("/redeliver", webhookHandler.Redeliver)
 	}
 
-	usersGroup := router.Group("/users")
+	integrationsGroup := admin.Group("/integrations")
 	{
-		usersGroup.POST("/setIsActive", userHandler.SetIsActive)
-		usersGroup.GET("/getReview", userHandler.GetUserReviews)
+		integrationsGroup.POST("/users/map", integrationHandler.MapUser)
+		integrationsGroup.GET("/users/list", integrationHandler.ListUsers)
+		integrationsGroup.GET("/syncFailures", integrationHandler.ListSyncFailures)
 	}
 
-	router.POST("/pullRequest/create", prHandler.CreatePR)
-	router.POST("/pullRequest/merge", prHandler.MergePR)
-	router.POST("/pullRequest/reassign", prHandler.ReassignPR)
+	remindersGroup := admin.Group("/reminders")
+	{
+		remindersGroup.GET("/status", reminderHandler.Status)
+		remindersGroup.POST("/setTeamThreshold", reminderHandler.SetTeamThreshold)
+	}
+
+	admin.GET("/notifications/deliveries", notificationHandler.ListDeliveries)
+
+	admin.GET("/stats/turnaround", statsHandler.Turnaround)
+
+	exportGroup := admin.Group("/export")
+	{
+		exportGroup.GET("/prs", exportHandler.PullRequests)
+		exportGroup.GET("/assignments", exportHandler.Assignments)
+	}
+
+	admin.POST("/import/teams", importHandler.ImportTeams)
+
+	// Ответ с выпущенным токеном не сохраняется для Idempotency-Key
+	tokensGroup := authenticated.Group("/auth/tokens", RequireRole(domain.RoleAdmin))
+	{
+		tokensGroup.POST("/add", authHandler.CreateToken)
+		tokensGroup.GET("/list", authHandler.ListTokens)
+		tokensGroup.POST("/revoke", authHandler.RevokeToken)
+	}
 }
 
 func ErrorMiddleware() gin.HandlerFunc {
@@ -42,11 +195,20 @@ func ErrorMiddleware() gin.HandlerFunc {
 		c.Next()
 
 		if len(c.Errors) > 0 {
+			ctx := c.Request.Context()
 			err := c.Errors.Last().Err
 			statusCode := HandleError(err)
 
+			// Непредвиденные ошибки логируем с контекстом запроса
+			if statusCode >= 500 {
+				logger.FromContext(ctx).Error("request failed", "error", err)
+			} else {
+				logger.FromContext(ctx).Info("request rejected", "error", err)
+			}
+
 			var errResp *domain.ErrorResponse
 			if errors.As(err, &errResp) {
+				errResp.RequestID = logger.RequestIDFromContext(ctx)
 				c.JSON(statusCode, errResp)
 			} else {
 
@@ -55,6 +217,7 @@ func ErrorMiddleware() gin.HandlerFunc {
 						Code:    domain.ErrUnknown,
 						Message: err.Error(),
 					},
+					RequestID: logger.RequestIDFromContext(ctx),
 				})
 			}
 
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+type ctxKey struct{}
+
+// holder позволяет обработчикам и сервисам дополнять логгер запроса
+// так, чтобы итоговая строка о запросе тоже содержала добавленные поля
+type holder struct {
+	mu     sync.RWMutex
+	logger *slog.Logger
+}
+
+// New создаёт JSON-логгер с уровнем из строки (debug, info, warn, error)
+func New(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+}
+
+// WithContext кладёт логгер в контекст
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &holder{logger: l})
+}
+
+// FromContext возвращает логгер из контекста или slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		return h.logger
+	}
+	return slog.Default()
+}
+
+// AddAttrs добавляет поля к логгеру из контекста, например идентификаторы PR
+// и пользователей, с которыми работает запрос
+func AddAttrs(ctx context.Context, args ...any) {
+	if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.logger = h.logger.With(args...)
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID сохраняет идентификатор запроса в контексте
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса или пустую строку
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Минимальное число ревьюверов, которое сервис старается назначить на PR
+const requiredReviewers = 2
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route, method and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	prsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pr_created_total",
+		Help: "Pull requests created.",
+	})
+
+	prsUnderstaffed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pr_created_understaffed_total",
+		Help: "Pull requests created with fewer than the required number of reviewers.",
+	})
+
+	prsMerged = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pr_merged_total",
+		Help: "Pull requests merged.",
+	})
+
+	reassignments = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pr_reassignments_total",
+		Help: "Successful reviewer reassignments.",
+	})
+
+	noCandidate = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pr_reassign_no_candidate_total",
+		Help: "Reassignments rejected with NO_CANDIDATE.",
+	})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_rate_limited_total",
+		Help: "Requests rejected by rate limiting, by route group.",
+	}, []string{"group"})
+
+	reminders = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "review_reminders_total",
+		Help: "Stale review reminders by result (sent, failed).",
+	}, []string{"result"})
+
+	escalations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "review_sla_escalations_total",
+		Help: "Review SLA escalations by result (reassigned, no_candidate, failed).",
+	}, []string{"result"})
+
+	notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "notifications_total",
+		Help: "Notification delivery attempts by channel and result (sent, failed, dead).",
+	}, []string{"channel", "result"})
+
+	digests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "review_digests_total",
+		Help: "Daily review digests by result (sent, empty, failed).",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		prsCreated,
+		prsUnderstaffed,
+		prsMerged,
+		reassignments,
+		noCandidate,
+		rateLimited,
+		reminders,
+		escalations,
+		notifications,
+		digests,
+	)
+}
+
+// Handler отдаёт метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterPool добавляет метрики пула соединений pgx
+func RegisterPool(pool *pgxpool.Pool) {
+	registry.MustRegister(newPoolCollector(pool))
+}
+
+// Middleware считает запросы и их длительность по шаблону маршрута
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// IncNoCandidate учитывает переназначение, для которого не нашлось кандидата
+func IncNoCandidate() {
+	noCandidate.Inc()
+}
+
+// IncRateLimited учитывает запрос, отклонённый лимитом частоты
+func IncRateLimited(group string) {
+	rateLimited.WithLabelValues(group).Inc()
+}
+
+// IncReminder учитывает попытку напоминания ревьюверу
+func IncReminder(result string) {
+	reminders.WithLabelValues(result).Inc()
+}
+
+// IncNotification учитывает попытку доставить уведомление по каналу
+func IncNotification(channel, result string) {
+	notifications.WithLabelValues(channel, result).Inc()
+}
+
+// IncDigest учитывает ежедневную сводку ревью
+func IncDigest(result string) {
+	digests.WithLabelValues(result).Inc()
+}
+
+// IncEscalation учитывает попытку переназначить ревью, превысившее SLA
+func IncEscalation(result string) {
+	escalations.WithLabelValues(result).Inc()
+}
+
+// HandleEvent - обработчик шины, считает доменные счётчики по событиям outbox,
+// чтобы повторный мердж или откат транзакции не искажали значения.
+// Повторно успешному подписчику событие не отдаётся (delivered_to), но если
+// инстанс упадёт между обработкой и этой отметкой, событие учтётся ещё раз:
+// счётчики считают at-least-once.
+func HandleEvent(_ context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventPRCreated:
+		var payload domain.PRCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		prsCreated.Inc()
+		if len(payload.Reviewers) < requiredReviewers {
+			prsUnderstaffed.Inc()
+		}
+	case domain.EventPRMerged:
+		prsMerged.Inc()
+	case domain.EventReviewerReassigned:
+		reassignments.Inc()
+	}
+	return nil
+}
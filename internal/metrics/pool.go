@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector снимает pool.Stat() при каждом scrape
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	totalConns       *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquireCount     *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+	emptyAcquire     *prometheus.Desc
+	emptyAcquireWait *prometheus.Desc
+	canceledAcquire  *prometheus.Desc
+}
+
+func newPoolCollector(pool *pgxpool.Pool) *poolCollector {
+	return &poolCollector{
+		pool: pool,
+		acquiredConns: prometheus.NewDesc("pgxpool_acquired_conns",
+			"Connections currently acquired from the pool.", nil, nil),
+		idleConns: prometheus.NewDesc("pgxpool_idle_conns",
+			"Idle connections in the pool.", nil, nil),
+		totalConns: prometheus.NewDesc("pgxpool_total_conns",
+			"Total connections in the pool.", nil, nil),
+		maxConns: prometheus.NewDesc("pgxpool_max_conns",
+			"Maximum size of the pool.", nil, nil),
+		acquireCount: prometheus.NewDesc("pgxpool_acquire_total",
+			"Successful connection acquires.", nil, nil),
+		acquireDuration: prometheus.NewDesc("pgxpool_acquire_duration_seconds_total",
+			"Total time spent acquiring connections.", nil, nil),
+		emptyAcquire: prometheus.NewDesc("pgxpool_empty_acquire_total",
+			"Acquires that had to wait for a connection.", nil, nil),
+		emptyAcquireWait: prometheus.NewDesc("pgxpool_empty_acquire_wait_seconds_total",
+			"Total time spent waiting for a connection when the pool was empty.", nil, nil),
+		canceledAcquire: prometheus.NewDesc("pgxpool_canceled_acquire_total",
+			"Acquires canceled by context.", nil, nil),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.emptyAcquireWait
+	ch <- c.canceledAcquire
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireWait, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
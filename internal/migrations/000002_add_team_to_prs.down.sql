@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS prs_team_id_idx;
+ALTER TABLE prs DROP COLUMN IF EXISTS team_id;
@@ -0,0 +1,9 @@
+-- команда, которой принадлежит PR (фиксируется при создании)
+ALTER TABLE prs ADD COLUMN IF NOT EXISTS team_id UUID REFERENCES teams(id) ON DELETE RESTRICT;
+
+-- заполняем команду для уже существующих PR по текущей команде автора
+UPDATE prs p
+SET team_id = (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = p.author_id LIMIT 1)
+WHERE p.team_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS prs_team_id_idx ON prs(team_id);
@@ -0,0 +1,19 @@
+-- история назначений ревьюверов
+CREATE TABLE IF NOT EXISTS pr_reviewer_history (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id UUID NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+    event TEXT NOT NULL CHECK (event IN ('ASSIGNED','REASSIGNED_FROM','REASSIGNED_TO')),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    related_user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    reason TEXT,
+    actor TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS pr_reviewer_history_pr_id_idx ON pr_reviewer_history(pr_id, created_at);
+
+-- текущие назначения считаем выполненными при создании PR
+INSERT INTO pr_reviewer_history (pr_id, event, user_id, reason, created_at)
+SELECT r.pr_id, 'ASSIGNED', r.user_id, 'backfilled', COALESCE(p.created_at, now())
+FROM pr_reviewers r
+JOIN prs p ON p.id = r.pr_id;
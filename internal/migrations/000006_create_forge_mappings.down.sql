@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS forge_pull_requests;
+DROP TABLE IF EXISTS forge_users;
@@ -0,0 +1,20 @@
+-- соответствие пользователей GitHub/GitLab пользователям сервиса
+CREATE TABLE IF NOT EXISTS forge_users (
+    forge TEXT NOT NULL CHECK (forge IN ('github','gitlab')),
+    forge_username TEXT NOT NULL,
+    forge_user_id BIGINT,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (forge, forge_username)
+);
+
+CREATE INDEX IF NOT EXISTS forge_users_user_id_idx ON forge_users(user_id);
+
+-- PR на стороне GitHub/GitLab, для которых создан PR в сервисе
+CREATE TABLE IF NOT EXISTS forge_pull_requests (
+    forge TEXT NOT NULL CHECK (forge IN ('github','gitlab')),
+    repository TEXT NOT NULL,
+    number BIGINT NOT NULL,
+    pr_id UUID NOT NULL UNIQUE REFERENCES prs(id) ON DELETE CASCADE,
+    url TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (forge, repository, number)
+);
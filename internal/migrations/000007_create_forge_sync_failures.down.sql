@@ -0,0 +1 @@
+DROP TABLE IF EXISTS forge_sync_failures;
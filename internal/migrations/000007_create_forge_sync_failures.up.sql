@@ -0,0 +1,11 @@
+-- неудачные попытки передать ревьюверов на GitHub/GitLab
+CREATE TABLE IF NOT EXISTS forge_sync_failures (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id UUID NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+    forge TEXT NOT NULL,
+    repository TEXT NOT NULL,
+    number BIGINT NOT NULL,
+    event_id BIGINT NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
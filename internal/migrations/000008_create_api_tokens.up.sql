@@ -0,0 +1,11 @@
+-- токены доступа к API, храним только sha256
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('admin','user')),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CHECK (role = 'admin' OR user_id IS NOT NULL)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS prs_open_created_at_idx;
+DROP TABLE IF EXISTS review_reminders;
+ALTER TABLE teams DROP COLUMN IF EXISTS reminder_after_minutes;
@@ -0,0 +1,16 @@
+-- порог "зависшего" PR для команды, NULL -- значение из конфигурации
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reminder_after_minutes INT CHECK (reminder_after_minutes > 0);
+
+-- напоминания ревьюверам об открытых PR
+CREATE TABLE IF NOT EXISTS review_reminders (
+    pr_id UUID NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reminder_count INT NOT NULL DEFAULT 0,
+    last_reminded_at TIMESTAMP WITH TIME ZONE,
+    -- напоминание захвачено планировщиком до этого времени
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    PRIMARY KEY (pr_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS prs_open_created_at_idx ON prs(created_at) WHERE status = 'OPEN';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS pr_reviewers_pending_idx;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS verdict_at;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS verdict;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS assigned_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_sla_minutes;
@@ -0,0 +1,18 @@
+-- SLA ревью команды, NULL -- без автоматической эскалации
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS review_sla_minutes INT CHECK (review_sla_minutes > 0);
+
+-- когда ревьювер назначен и какой вердикт он вынес
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS verdict TEXT CHECK (verdict IN ('APPROVED','CHANGES_REQUESTED'));
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS verdict_at TIMESTAMP WITH TIME ZONE;
+
+-- время назначения текущих ревьюверов берём из истории
+UPDATE pr_reviewers r
+SET assigned_at = COALESCE(
+    (SELECT max(h.created_at) FROM pr_reviewer_history h
+     WHERE h.pr_id = r.pr_id AND h.user_id = r.user_id AND h.event IN ('ASSIGNED','REASSIGNED_TO')),
+    (SELECT p.created_at FROM prs p WHERE p.id = r.pr_id),
+    r.assigned_at
+);
+
+CREATE INDEX IF NOT EXISTS pr_reviewers_pending_idx ON pr_reviewers(assigned_at) WHERE verdict IS NULL;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS team_holidays;
+ALTER TABLE users DROP COLUMN IF EXISTS work_days;
+ALTER TABLE users DROP COLUMN IF EXISTS work_end;
+ALTER TABLE users DROP COLUMN IF EXISTS work_start;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+ALTER TABLE teams DROP COLUMN IF EXISTS work_days;
+ALTER TABLE teams DROP COLUMN IF EXISTS work_end;
+ALTER TABLE teams DROP COLUMN IF EXISTS work_start;
+ALTER TABLE teams DROP COLUMN IF EXISTS timezone;
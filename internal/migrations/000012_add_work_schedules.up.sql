@@ -0,0 +1,18 @@
+-- рабочие часы команды; пользователь может переопределить любое поле
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS timezone TEXT;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS work_start TEXT CHECK (work_start ~ '^[0-9]{2}:[0-9]{2}$');
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS work_end TEXT CHECK (work_end ~ '^[0-9]{2}:[0-9]{2}$');
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS work_days INT[];
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_start TEXT CHECK (work_start ~ '^[0-9]{2}:[0-9]{2}$');
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_end TEXT CHECK (work_end ~ '^[0-9]{2}:[0-9]{2}$');
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_days INT[];
+
+-- праздники команды
+CREATE TABLE IF NOT EXISTS team_holidays (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    name TEXT,
+    PRIMARY KEY (team_id, day)
+);
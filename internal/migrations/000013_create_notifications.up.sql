@@ -0,0 +1,33 @@
+-- каналы уведомлений пользователя; kinds NULL -- все виды уведомлений
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL CHECK (channel IN ('EMAIL','SLACK','MATTERMOST')),
+    address TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    kinds TEXT[],
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, channel)
+);
+
+-- очередь и журнал доставки уведомлений, FAILED -- исчерпали попытки
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    address TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    -- источник уведомления (событие outbox или напоминание), защищает от дублей
+    dedup_key TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING','SENT','FAILED')) DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (dedup_key, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS notification_deliveries_pending_idx ON notification_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS notification_deliveries_user_idx ON notification_deliveries(user_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS html_body;
+
+DROP TABLE IF EXISTS review_digests;
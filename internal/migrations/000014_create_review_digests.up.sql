@@ -0,0 +1,14 @@
+-- ежедневная сводка открытых ревью; send_at и days - по часовому поясу timezone
+CREATE TABLE IF NOT EXISTS review_digests (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    send_at TEXT NOT NULL DEFAULT '09:00' CHECK (send_at ~ '^[0-9]{2}:[0-9]{2}$'),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    days INT[] NOT NULL DEFAULT '{1,2,3,4,5}',
+    -- локальная дата последней сводки, не больше одной в день
+    last_sent_on DATE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- HTML-версия уведомления для почты
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS html_body TEXT;
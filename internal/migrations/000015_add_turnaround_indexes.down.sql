@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pr_reviewers_verdict_at_idx;
+DROP INDEX IF EXISTS prs_merged_at_idx;
@@ -0,0 +1,3 @@
+-- выборки /stats/turnaround по дате слияния и дате вердикта
+CREATE INDEX IF NOT EXISTS prs_merged_at_idx ON prs(merged_at) WHERE status = 'MERGED';
+CREATE INDEX IF NOT EXISTS pr_reviewers_verdict_at_idx ON pr_reviewers(verdict_at) WHERE verdict_at IS NOT NULL;
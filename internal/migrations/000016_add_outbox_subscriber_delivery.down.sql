@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS outbox_events_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events(next_attempt_at, id) WHERE dispatched_at IS NULL;
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS dead_at,
+    DROP COLUMN IF EXISTS delivered_to;
@@ -0,0 +1,8 @@
+-- Доставка событий учитывается по подписчикам: при повторе событие получают
+-- только те, кто его ещё не обработал. После исчерпания попыток событие мёртвое.
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS delivered_to TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS outbox_events_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events(next_attempt_at, id) WHERE dispatched_at IS NULL AND dead_at IS NULL;
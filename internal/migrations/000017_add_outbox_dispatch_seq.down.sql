@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_events_dispatch_seq_idx;
+
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS dispatch_seq;
+
+DROP SEQUENCE IF EXISTS outbox_dispatch_seq;
@@ -0,0 +1,20 @@
+-- Номер доставки: выдаётся при фиксации доставки, по нему поток событий
+-- возобновляется с Last-Event-ID. id задаётся при записи в outbox, а события
+-- доставляются не по порядку id (повторы, параллельные диспетчеры).
+CREATE SEQUENCE IF NOT EXISTS outbox_dispatch_seq;
+
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS dispatch_seq BIGINT;
+
+-- Уже доставленные события нумеруются в порядке доставки
+UPDATE outbox_events o
+SET dispatch_seq = numbered.seq
+FROM (
+    SELECT id, row_number() OVER (ORDER BY dispatched_at, id) AS seq
+    FROM outbox_events
+    WHERE dispatched_at IS NOT NULL
+) numbered
+WHERE o.id = numbered.id;
+
+SELECT setval('outbox_dispatch_seq', COALESCE(max(dispatch_seq), 0) + 1, false) FROM outbox_events;
+
+CREATE UNIQUE INDEX IF NOT EXISTS outbox_events_dispatch_seq_idx ON outbox_events(dispatch_seq) WHERE dispatch_seq IS NOT NULL;
@@ -0,0 +1,143 @@
+// Package migrations содержит миграции схемы, встроенные в бинарник.
+// Версия схемы хранится в schema_migrations в формате golang-migrate,
+// поэтому базы, мигрированные контейнером migrate/migrate, подхватываются как есть.
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration - встроенная миграция
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// List возвращает встроенные миграции по возрастанию версии
+func List() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Migration
+	for _, entry := range entries {
+		m, err := source.Parse(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		if m.Direction == source.Up {
+			list = append(list, Migration{Version: m.Version, Name: m.Identifier})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Latest возвращает версию схемы, которую ожидает бинарник
+func Latest() (uint, error) {
+	list, err := List()
+	if err != nil {
+		return 0, err
+	}
+	if len(list) == 0 {
+		return 0, errors.New("no embedded migrations")
+	}
+	return list[len(list)-1].Version, nil
+}
+
+// Migrator применяет встроенные миграции к базе
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// New открывает отдельное соединение к базе для миграций
+func New(connConfig *pgx.ConnConfig) (*Migrator, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+	driver, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	m.Log = slogLogger{}
+	return &Migrator{m: m}, nil
+}
+
+// Up применяет все ещё не применённые миграции
+func (mg *Migrator) Up() error {
+	return ignoreNoChange(mg.m.Up())
+}
+
+// Down откатывает steps последних миграций, steps <= 0 - все
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return ignoreNoChange(mg.m.Down())
+	}
+	return ignoreNoChange(mg.m.Steps(-steps))
+}
+
+// Force записывает версию без выполнения миграций и снимает признак dirty.
+// Нужна после ручного исправления упавшей миграции.
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+// Version возвращает текущую версию схемы, 0 - миграции не применялись.
+// dirty = true, если последняя миграция упала на середине.
+func (mg *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// slogLogger пишет ход миграций в slog
+type slogLogger struct{}
+
+func (slogLogger) Printf(format string, v ...any) {
+	slog.Info(strings.TrimSpace(fmt.Sprintf(format, v...)), "component", "migrations")
+}
+
+func (slogLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,283 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/netip"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Channel доставляет отрисованное уведомление на адрес получателя
+type Channel interface {
+	Send(ctx context.Context, address string, msg Message) error
+}
+
+// SMTPConfig - настройки отправки почты
+type SMTPConfig struct {
+	// host:port SMTP-сервера
+	Addr string
+	From string
+	// Пустое имя - без аутентификации
+	Username string
+	Password string
+	Timeout  time.Duration
+	// Настройки STARTTLS, nil - проверка по системным корневым сертификатам
+	TLSConfig *tls.Config
+}
+
+// EmailChannel отправляет уведомления письмами через SMTP.
+// STARTTLS используется, если сервер его предлагает.
+type EmailChannel struct {
+	cfg SMTPConfig
+}
+
+func NewEmailChannel(cfg SMTPConfig) *EmailChannel {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &EmailChannel{cfg: cfg}
+}
+
+func (e *EmailChannel) Send(ctx context.Context, address string, msg Message) error {
+	from, err := mail.ParseAddress(e.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(address)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	host, _, err := net.SplitHostPort(e.cfg.Addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig := &tls.Config{}
+		if e.cfg.TLSConfig != nil {
+			tlsConfig = e.cfg.TLSConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = host
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildEmail(from, to, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func buildEmail(from, to *mail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		writePart(&b, "text/plain", msg.Body)
+		return b.Bytes()
+	}
+
+	// Текст и HTML одного письма, почтовый клиент показывает подходящую версию
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", msg.Body},
+		{"text/html", msg.HTML},
+	} {
+		w, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		_, _ = w.Write(crlf(part.content))
+	}
+	_ = mw.Close()
+	return b.Bytes()
+}
+
+func writePart(b *bytes.Buffer, contentType, content string) {
+	fmt.Fprintf(b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.Write(crlf(content))
+}
+
+// crlf переводит строки в CRLF, как требует SMTP
+func crlf(content string) []byte {
+	var b bytes.Buffer
+	for _, line := range strings.Split(content, "\n") {
+		b.WriteString(strings.TrimRight(line, "\r"))
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
+
+// ChatChannel отправляет уведомления во входящий вебхук Slack или Mattermost.
+// Адрес получателя - URL вебхука на одном из разрешённых хостов.
+type ChatChannel struct {
+	client *http.Client
+	hosts  ChatHosts
+	// Разметка жирного шрифта для темы: * в Slack, ** в Mattermost
+	bold string
+}
+
+func NewSlackChannel(client *http.Client, hosts ChatHosts) *ChatChannel {
+	return &ChatChannel{client: client, hosts: hosts, bold: "*"}
+}
+
+func NewMattermostChannel(client *http.Client, hosts ChatHosts) *ChatChannel {
+	return &ChatChannel{client: client, hosts: hosts, bold: "**"}
+}
+
+func (c *ChatChannel) Send(ctx context.Context, address string, msg Message) error {
+	// Адрес мог быть сохранён до того, как хост убрали из списка
+	if !c.hosts.Allows(address) {
+		return fmt.Errorf("webhook host is not allowed")
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"text": c.bold + msg.Subject + c.bold + "\n" + msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ChatHosts - хосты, на которые разрешено отправлять вебхуки чатов. URL вебхука
+// задаёт пользователь, поэтому без списка сервис отправлял бы запросы
+// на любой адрес внутри сети.
+type ChatHosts map[string]struct{}
+
+// ParseChatHosts разбирает список хостов через запятую, регистр не важен
+func ParseChatHosts(list string) ChatHosts {
+	hosts := ChatHosts{}
+	for _, host := range strings.Split(list, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts[host] = struct{}{}
+		}
+	}
+	return hosts
+}
+
+// Allows - абсолютный http(s) URL на одном из хостов списка
+func (h ChatHosts) Allows(address string) bool {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return false
+	}
+	_, ok := h[strings.ToLower(u.Hostname())]
+	return ok
+}
+
+// NewChatHTTPClient - клиент для вебхуков чатов. Прокси из окружения не
+// используются, а соединения с loopback, link-local (включая метаданные облака)
+// и неуказанными адресами запрещены уже после разрешения имени, поэтому
+// разрешённый хост не перенаправить на них через DNS. Частные сети
+// не запрещаются: Mattermost часто развёрнут внутри сети.
+func NewChatHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: denyLocalAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Редиректы не выполняются: они увели бы запрос с разрешённого хоста
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func denyLocalAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("connection to %s is not allowed", ip)
+	}
+	return nil
+}
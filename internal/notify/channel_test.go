@@ -0,0 +1,363 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// smtpSession - что fake SMTP-сервер получил за одно соединение
+type smtpSession struct {
+	tls      bool
+	authTLS  bool
+	auth     string
+	mailFrom string
+	rcptTo   []string
+	data     string
+}
+
+// fakeSMTP - SMTP-сервер на одно соединение, STARTTLS предлагается при startTLS
+type fakeSMTP struct {
+	addr     string
+	tls      *tls.Config
+	sessions chan smtpSession
+}
+
+func newFakeSMTP(t *testing.T, startTLS bool) (*fakeSMTP, *x509.CertPool) {
+	t.Helper()
+
+	// Сертификат httptest выписан на 127.0.0.1
+	certServer := httptest.NewTLSServer(nil)
+	t.Cleanup(certServer.Close)
+	roots := x509.NewCertPool()
+	roots.AddCert(certServer.Certificate())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{addr: ln.Addr().String(), sessions: make(chan smtpSession, 1)}
+	if startTLS {
+		s.tls = &tls.Config{Certificates: certServer.TLS.Certificates}
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		s.sessions <- s.serve(conn)
+	}()
+	return s, roots
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) smtpSession {
+	var session smtpSession
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return session
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(cmd, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			reply("250-localhost")
+			if s.tls != nil && !session.tls {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return session
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			session.tls = true
+		case "AUTH":
+			session.auth = arg
+			session.authTLS = session.tls
+			reply("235 authenticated")
+		case "MAIL":
+			session.mailFrom = arg
+			reply("250 ok")
+		case "RCPT":
+			session.rcptTo = append(session.rcptTo, arg)
+			reply("250 ok")
+		case "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			// Строки читаются как есть, чтобы проверить окончания CRLF
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return session
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			session.data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return session
+		default:
+			reply("502 unknown command")
+		}
+	}
+}
+
+func (s *fakeSMTP) session(t *testing.T) smtpSession {
+	t.Helper()
+	select {
+	case session := <-s.sessions:
+		return session
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP session did not finish")
+		return smtpSession{}
+	}
+}
+
+func TestEmailChannelSendsOverStartTLSWithAuth(t *testing.T) {
+	server, roots := newFakeSMTP(t, true)
+	channel := NewEmailChannel(SMTPConfig{
+		Addr:      server.addr,
+		From:      "AvitoPR <bot@example.com>",
+		Username:  "bot",
+		Password:  "s3cret",
+		TLSConfig: &tls.Config{RootCAs: roots},
+	})
+
+	err := channel.Send(context.Background(), "Алиса <alice@example.com>", Message{
+		Subject: "Новое ревью",
+		Body:    "Вас назначили ревьювером\nPR-1001",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := server.session(t)
+
+	if !session.tls || !session.authTLS {
+		t.Errorf("tls = %v, auth over tls = %v, want STARTTLS before AUTH", session.tls, session.authTLS)
+	}
+	if want := "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00bot\x00s3cret")); session.auth != want {
+		t.Errorf("AUTH %q, want %q", session.auth, want)
+	}
+	if session.mailFrom != "FROM:<bot@example.com>" {
+		t.Errorf("MAIL %q, want FROM:<bot@example.com>", session.mailFrom)
+	}
+	if len(session.rcptTo) != 1 || session.rcptTo[0] != "TO:<alice@example.com>" {
+		t.Errorf("RCPT %q, want TO:<alice@example.com>", session.rcptTo)
+	}
+
+	if strings.Contains(strings.ReplaceAll(session.data, "\r\n", ""), "\n") {
+		t.Errorf("message has bare LF line endings: %q", session.data)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{
+		"From":         `"AvitoPR" <bot@example.com>`,
+		"To":           "=?utf-8?q?=D0=90=D0=BB=D0=B8=D1=81=D0=B0?= <alice@example.com>",
+		"Subject":      "=?utf-8?q?=D0=9D=D0=BE=D0=B2=D0=BE=D0=B5_=D1=80=D0=B5=D0=B2=D1=8C=D1=8E?=",
+		"Mime-Version": "1.0",
+		"Content-Type": "text/plain; charset=utf-8",
+	}
+	for name, want := range headers {
+		if got := msg.Header.Get(name); got != want {
+			t.Errorf("%s: %q, want %q", name, got, want)
+		}
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+	if !strings.HasSuffix(session.data, "\r\n\r\nВас назначили ревьювером\r\nPR-1001\r\n") {
+		t.Errorf("body is not CRLF-terminated text: %q", session.data)
+	}
+}
+
+func TestEmailChannelSendsHTMLAlternative(t *testing.T) {
+	server, roots := newFakeSMTP(t, true)
+	channel := NewEmailChannel(SMTPConfig{
+		Addr:      server.addr,
+		From:      "bot@example.com",
+		TLSConfig: &tls.Config{RootCAs: roots},
+	})
+
+	err := channel.Send(context.Background(), "alice@example.com", Message{
+		Subject: "Digest",
+		Body:    "line 1\nline 2",
+		HTML:    "<p>line 1</p>\n<p>line 2</p>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := server.session(t)
+
+	if session.auth != "" {
+		t.Errorf("AUTH %q sent without credentials", session.auth)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/alternative; boundary=") {
+		t.Errorf("Content-Type %q, want multipart/alternative", ct)
+	}
+	for _, want := range []string{
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"line 1\r\nline 2\r\n",
+		"Content-Type: text/html; charset=utf-8\r\n",
+		"<p>line 1</p>\r\n<p>line 2</p>\r\n",
+	} {
+		if !strings.Contains(session.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, session.data)
+		}
+	}
+}
+
+func TestEmailChannelWithoutStartTLS(t *testing.T) {
+	server, _ := newFakeSMTP(t, false)
+	channel := NewEmailChannel(SMTPConfig{Addr: server.addr, From: "bot@example.com"})
+
+	if err := channel.Send(context.Background(), "alice@example.com", Message{Subject: "Hi", Body: "text"}); err != nil {
+		t.Fatal(err)
+	}
+	session := server.session(t)
+	if session.tls || session.auth != "" {
+		t.Errorf("tls = %v, auth = %q, want a plain session without AUTH", session.tls, session.auth)
+	}
+	if len(session.rcptTo) != 1 || !strings.HasSuffix(session.data, "\r\n\r\ntext\r\n") {
+		t.Errorf("rcpt %q, data %q", session.rcptTo, session.data)
+	}
+}
+
+func TestEmailChannelRejectsUntrustedCertificate(t *testing.T) {
+	server, _ := newFakeSMTP(t, true)
+	// Без RootCAs самоподписанный сертификат сервера не проходит проверку
+	channel := NewEmailChannel(SMTPConfig{Addr: server.addr, From: "bot@example.com", Username: "bot", Password: "s3cret"})
+
+	if err := channel.Send(context.Background(), "alice@example.com", Message{Subject: "Hi", Body: "text"}); err == nil {
+		t.Fatal("Send succeeded with an untrusted certificate")
+	}
+	if session := server.session(t); session.auth != "" || session.mailFrom != "" {
+		t.Errorf("auth = %q, mail = %q, want nothing sent after failed STARTTLS", session.auth, session.mailFrom)
+	}
+}
+
+func TestChatHostsAllows(t *testing.T) {
+	hosts := ParseChatHosts(" hooks.slack.com, Chat.Example.com ,")
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{"https://chat.example.com:8065/hooks/abc", true},
+		{"http://CHAT.example.com/hooks/abc", true},
+		{"https://example.com/hooks/abc", false},
+		{"https://hooks.slack.com.evil.test/services", false},
+		{"https://evil.test/?next=https://hooks.slack.com", false},
+		{"https://user@hooks.slack.com/services", false},
+		{"ftp://hooks.slack.com/services", false},
+		{"hooks.slack.com/services", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+	}
+	for _, tt := range tests {
+		if got := hosts.Allows(tt.address); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestChatChannelSendsOnlyToAllowedHosts(t *testing.T) {
+	requests := make(chan string, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- string(body)
+	}))
+	defer receiver.Close()
+
+	msg := Message{Subject: "Review", Body: "PR-1"}
+
+	allowed := NewSlackChannel(receiver.Client(), ParseChatHosts("127.0.0.1"))
+	if err := allowed.Send(context.Background(), receiver.URL+"/hook", msg); err != nil {
+		t.Fatalf("Send to an allowed host: %v", err)
+	}
+	if got := <-requests; got != `{"text":"*Review*\nPR-1"}` {
+		t.Errorf("payload = %s", got)
+	}
+
+	denied := NewSlackChannel(receiver.Client(), ParseChatHosts("hooks.slack.com"))
+	if err := denied.Send(context.Background(), receiver.URL+"/hook", msg); err == nil {
+		t.Fatal("Send to a host outside the list succeeded")
+	}
+	select {
+	case <-requests:
+		t.Error("request reached a host outside the list")
+	default:
+	}
+}
+
+func TestChatHTTPClientRefusesLocalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer receiver.Close()
+
+	// Даже разрешённый хост не пропускается на loopback после разрешения имени
+	channel := NewMattermostChannel(NewChatHTTPClient(time.Second), ParseChatHosts("127.0.0.1,localhost"))
+	port := receiver.URL[strings.LastIndex(receiver.URL, ":"):]
+	for _, address := range []string{receiver.URL, "http://localhost" + port} {
+		if err := channel.Send(context.Background(), address, Message{Subject: "s", Body: "b"}); err == nil {
+			t.Errorf("Send to %s succeeded", address)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("local receiver got %d requests", n)
+	}
+
+	tests := []struct {
+		address string
+		denied  bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"10.0.0.5:8065", false},
+		{"203.0.113.7:443", false},
+	}
+	for _, tt := range tests {
+		if err := denyLocalAddress("tcp", tt.address, nil); (err != nil) != tt.denied {
+			t.Errorf("denyLocalAddress(%s) = %v, want denied = %v", tt.address, err, tt.denied)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	batchSize = 50
+	// Время, на которое уведомление захватывается одним отправщиком
+	claimLease = time.Minute
+	// Предел одной отправки: последняя отправка пачки, начатая до claimLease/2,
+	// успевает завершиться до истечения захвата
+	sendTimeout = claimLease / 4
+	maxBackoff  = time.Hour
+)
+
+// Notifier ставит уведомления в очередь по каналам пользователя и отправляет их.
+// Реализует reminder.Notifier.
+type Notifier struct {
+	repo        repository.NotificationRepository
+	prRepo      repository.PrRepository
+	templates   *Templates
+	channels    map[domain.NotificationChannel]Channel
+	interval    time.Duration
+	maxAttempts int
+	baseBackoff time.Duration
+}
+
+// NewNotifier создаёт Notifier. Уведомления по каналам, которых нет в channels
+// (например, почта без SMTP), не ставятся в очередь.
+func NewNotifier(
+	repo repository.NotificationRepository,
+	prRepo repository.PrRepository,
+	templates *Templates,
+	channels map[domain.NotificationChannel]Channel,
+	interval time.Duration,
+	maxAttempts int,
+	baseBackoff time.Duration,
+) *Notifier {
+	return &Notifier{
+		repo:        repo,
+		prRepo:      prRepo,
+		templates:   templates,
+		channels:    channels,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+	}
+}
+
+// Enqueue - обработчик шины событий REVIEWER_ASSIGNED и REVIEWER_REASSIGNED,
+// уведомляет назначенного ревьювера
+func (n *Notifier) Enqueue(ctx context.Context, event domain.Event) error {
+	var data Data
+	switch event.Type {
+	case domain.EventReviewerAssigned:
+		var p domain.ReviewerAssignedPayload
+		if err := json.Unmarshal(event.Payload, &p); err != nil {
+			return err
+		}
+		data = Data{
+			Kind:          domain.NotificationReviewerAssigned,
+			PullRequestID: p.PullRequestID,
+			TeamName:      p.TeamName,
+			ReviewerID:    p.ReviewerID,
+		}
+	case domain.EventReviewerReassigned:
+		var p domain.ReviewerReassignedPayload
+		if err := json.Unmarshal(event.Payload, &p); err != nil {
+			return err
+		}
+		data = Data{
+			Kind:          domain.NotificationReviewerReassigned,
+			PullRequestID: p.PullRequestID,
+			TeamName:      p.TeamName,
+			ReviewerID:    p.NewReviewerID,
+			OldReviewerID: p.OldReviewerID,
+			Reason:        p.Reason,
+			Actor:         p.Actor,
+		}
+	default:
+		return nil
+	}
+
+	pr, err := n.prRepo.GetByID(ctx, data.PullRequestID)
+	if err != nil {
+		// PR уже удалён - уведомлять не о чем
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	data.PullRequestName = pr.PullRequestName
+	if pr.CreatedAt != nil {
+		data.OpenedAt = *pr.CreatedAt
+	}
+
+	_, err = n.enqueue(ctx, "event:"+strconv.FormatInt(event.ID, 10), data)
+	return err
+}
+
+// Notify ставит в очередь напоминание ревьюверу о зависшем PR
+func (n *Notifier) Notify(ctx context.Context, reminder domain.ReviewReminder) error {
+	queued, err := n.enqueue(ctx,
+		fmt.Sprintf("reminder:%s:%s:%d", reminder.PullRequestID, reminder.ReviewerID, reminder.Count),
+		Data{
+			Kind:            domain.NotificationReviewReminder,
+			PullRequestID:   reminder.PullRequestID,
+			PullRequestName: reminder.PullRequestName,
+			TeamName:        reminder.TeamName,
+			ReviewerID:      reminder.ReviewerID,
+			Count:           reminder.Count,
+			OpenedAt:        reminder.OpenedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Без настроенных каналов напоминание остаётся только в логе
+	if queued == 0 {
+		logger.FromContext(ctx).Info("review reminder",
+			"pr_id", reminder.PullRequestID,
+			"reviewer_id", reminder.ReviewerID,
+			"team_name", reminder.TeamName,
+			"count", reminder.Count,
+		)
+	}
+	return nil
+}
+
+// NotifyDigest ставит в очередь ежедневную сводку, не больше одной на локальную дату
+func (n *Notifier) NotifyDigest(ctx context.Context, digest domain.ReviewDigest) error {
+	now := time.Now()
+	items := make([]DigestItem, 0, len(digest.PullRequests))
+	for _, pr := range digest.PullRequests {
+		item := DigestItem{
+			ID:       pr.PullRequestID,
+			Name:     pr.PullRequestName,
+			AuthorID: pr.AuthorID,
+			URL:      pr.URL,
+		}
+		if pr.CreatedAt != nil {
+			item.OpenedAt = *pr.CreatedAt
+			item.Age = formatAge(now.Sub(*pr.CreatedAt))
+		}
+		items = append(items, item)
+	}
+
+	_, err := n.enqueue(ctx, "digest:"+digest.UserID+":"+digest.Date, Data{
+		Kind:         domain.NotificationDailyDigest,
+		ReviewerID:   digest.UserID,
+		Date:         digest.Date,
+		PullRequests: items,
+	})
+	return err
+}
+
+// enqueue ставит уведомление в очередь по всем подходящим каналам получателя
+// и возвращает число каналов
+func (n *Notifier) enqueue(ctx context.Context, dedupKey string, data Data) (int, error) {
+	prefs, err := n.repo.ListPreferences(ctx, data.ReviewerID)
+	if err != nil {
+		return 0, err
+	}
+
+	var deliveries []domain.NotificationDelivery
+	var msg *Message
+	for _, pref := range prefs {
+		if !pref.Enabled || (len(pref.Kinds) > 0 && !slices.Contains(pref.Kinds, data.Kind)) {
+			continue
+		}
+		if _, ok := n.channels[pref.Channel]; !ok {
+			logger.FromContext(ctx).Debug("notification channel not configured", "channel", pref.Channel, "user_id", pref.UserID)
+			continue
+		}
+
+		// Шаблон отрисовывается один раз и только если есть кому отправлять
+		if msg == nil {
+			rendered, err := n.templates.Render(data)
+			if err != nil {
+				return 0, err
+			}
+			msg = &rendered
+		}
+
+		deliveries = append(deliveries, domain.NotificationDelivery{
+			UserID:   pref.UserID,
+			Channel:  pref.Channel,
+			Address:  pref.Address,
+			Kind:     data.Kind,
+			DedupKey: dedupKey,
+			Subject:  msg.Subject,
+			Body:     msg.Body,
+			HTML:     msg.HTML,
+		})
+	}
+
+	if err := n.repo.Enqueue(ctx, deliveries); err != nil {
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+// Run блокируется до отмены ctx
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		n.sendPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifier) sendPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimedAt := time.Now()
+		pending, err := n.repo.Claim(ctx, batchSize, claimLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Error("notification claim failed", "error", err)
+			}
+			return
+		}
+
+		for _, d := range pending {
+			// Пачка из 50 медленных отправок не укладывается в захват. Остаток
+			// снова станет доступен, когда захват истечёт: иначе другой
+			// экземпляр перезахватит его и отправит уведомления повторно.
+			if time.Since(claimedAt) > claimLease/2 || ctx.Err() != nil {
+				return
+			}
+			n.deliver(ctx, d)
+		}
+
+		if len(pending) < batchSize {
+			return
+		}
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, d domain.NotificationDelivery) {
+	err := fmt.Errorf("channel %s is not configured", d.Channel)
+	if channel, ok := n.channels[d.Channel]; ok {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = channel.Send(sendCtx, d.Address, Message{Subject: d.Subject, Body: d.Body, HTML: d.HTML})
+		cancel()
+	}
+
+	if err == nil {
+		metrics.IncNotification(string(d.Channel), "sent")
+		if err := n.repo.MarkSent(ctx, d.DeliveryID); err != nil {
+			logger.FromContext(ctx).Error("notification mark sent failed", "delivery_id", d.DeliveryID, "error", err)
+		}
+		return
+	}
+
+	// Попытки считаются с единицы: текущая ещё не учтена в d.Attempts
+	attempt := d.Attempts + 1
+	var nextAttemptAt *time.Time
+	if attempt < n.maxAttempts {
+		next := time.Now().Add(n.backoff(attempt))
+		nextAttemptAt = &next
+		metrics.IncNotification(string(d.Channel), "failed")
+	} else {
+		metrics.IncNotification(string(d.Channel), "dead")
+	}
+
+	logger.FromContext(ctx).Warn("notification delivery failed",
+		"delivery_id", d.DeliveryID,
+		"user_id", d.UserID,
+		"channel", d.Channel,
+		"attempt", attempt,
+		"dead", nextAttemptAt == nil,
+		"error", err,
+	)
+	if err := n.repo.MarkFailed(ctx, d.DeliveryID, err.Error(), nextAttemptAt); err != nil {
+		logger.FromContext(ctx).Error("notification mark failed failed", "delivery_id", d.DeliveryID, "error", err)
+	}
+}
+
+// backoff - экспоненциальная задержка перед попыткой attempt+1
+func (n *Notifier) backoff(attempt int) time.Duration {
+	d := n.baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// formatAge округляет длительность до часов: "45m", "5h", "3d 4h"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	days := int(d.Hours()) / 24
+	if hours := int(d.Hours()) % 24; hours > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dd", days)
+}
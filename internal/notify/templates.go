@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Data - данные, доступные шаблонам уведомлений
+type Data struct {
+	Kind            domain.NotificationKind
+	PullRequestID   string
+	PullRequestName string
+	TeamName        string
+	ReviewerID      string
+	// Предыдущий ревьювер при переназначении
+	OldReviewerID string
+	Reason        string
+	Actor         string
+	// Номер напоминания
+	Count    int
+	OpenedAt time.Time
+	// Сводка: локальная дата и PR от старых к новым
+	Date         string
+	PullRequests []DigestItem
+}
+
+// DigestItem - PR в ежедневной сводке
+type DigestItem struct {
+	ID       string
+	Name     string
+	AuthorID string
+	// Пусто, если ссылки нет
+	URL      string
+	OpenedAt time.Time
+	// Сколько PR открыт, например "3d 4h"
+	Age string
+}
+
+// Message - отрисованное уведомление
+type Message struct {
+	Subject string
+	Body    string
+	// HTML-версия для почты, пусто - только текст
+	HTML string
+}
+
+// Шаблоны по умолчанию: тема и текст для каждого вида уведомления
+var defaultTemplates = map[domain.NotificationKind][2]string{
+	domain.NotificationReviewerAssigned: {
+		`Review requested: {{.PullRequestName}}`,
+		`You were assigned to review "{{.PullRequestName}}" ({{.PullRequestID}}) in team {{.TeamName}}.`,
+	},
+	domain.NotificationReviewerReassigned: {
+		`Review handed over: {{.PullRequestName}}`,
+		`You were assigned to review "{{.PullRequestName}}" ({{.PullRequestID}}) in team {{.TeamName}} instead of {{.OldReviewerID}}.
+{{- if .Reason}}
+Reason: {{.Reason}}{{end}}`,
+	},
+	domain.NotificationReviewReminder: {
+		`Reminder: "{{.PullRequestName}}" is waiting for your review`,
+		`"{{.PullRequestName}}" ({{.PullRequestID}}) in team {{.TeamName}} has been open since {{.OpenedAt.UTC.Format "2006-01-02 15:04 UTC"}} and still needs your verdict.
+{{- if gt .Count 1}}
+This is reminder #{{.Count}}.{{end}}`,
+	},
+	domain.NotificationDailyDigest: {
+		`Pending reviews for {{.Date}}: {{len .PullRequests}}`,
+		`{{len .PullRequests}} pull request(s) are waiting for your review, oldest first:
+{{- range .PullRequests}}
+- {{.Name}} ({{.ID}}) by {{.AuthorID}}, open {{.Age}}{{if .URL}}
+  {{.URL}}{{end}}{{end}}`,
+	},
+}
+
+// HTML-шаблоны по умолчанию; для остальных видов письмо отправляется только текстом
+var defaultHTMLTemplates = map[domain.NotificationKind]string{
+	domain.NotificationDailyDigest: `<p>{{len .PullRequests}} pull request(s) are waiting for your review, oldest first:</p>
+<ul>
+{{- range .PullRequests}}
+  <li>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}} ({{.ID}}) by {{.AuthorID}}, open {{.Age}}</li>
+{{- end}}
+</ul>`,
+}
+
+// Templates - шаблоны темы и текста по видам уведомлений
+type Templates struct {
+	subjects map[domain.NotificationKind]*template.Template
+	bodies   map[domain.NotificationKind]*template.Template
+	htmls    map[domain.NotificationKind]*htmltemplate.Template
+}
+
+// LoadTemplates разбирает шаблоны по умолчанию и заменяет их файлами из dir:
+// <KIND>.subject.tmpl, <KIND>.body.tmpl и <KIND>.html.tmpl. Пустой dir - только
+// шаблоны по умолчанию.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{
+		subjects: map[domain.NotificationKind]*template.Template{},
+		bodies:   map[domain.NotificationKind]*template.Template{},
+		htmls:    map[domain.NotificationKind]*htmltemplate.Template{},
+	}
+
+	for kind, texts := range defaultTemplates {
+		subject, body, html := texts[0], texts[1], defaultHTMLTemplates[kind]
+		if dir != "" {
+			var err error
+			if subject, err = readOverride(dir, string(kind)+".subject.tmpl", subject); err != nil {
+				return nil, err
+			}
+			if body, err = readOverride(dir, string(kind)+".body.tmpl", body); err != nil {
+				return nil, err
+			}
+			if html, err = readOverride(dir, string(kind)+".html.tmpl", html); err != nil {
+				return nil, err
+			}
+		}
+
+		subjectTmpl, err := template.New(string(kind) + ".subject").Option("missingkey=error").Parse(subject)
+		if err != nil {
+			return nil, err
+		}
+		bodyTmpl, err := template.New(string(kind) + ".body").Option("missingkey=error").Parse(body)
+		if err != nil {
+			return nil, err
+		}
+		t.subjects[kind] = subjectTmpl
+		t.bodies[kind] = bodyTmpl
+
+		if html != "" {
+			htmlTmpl, err := htmltemplate.New(string(kind) + ".html").Option("missingkey=error").Parse(html)
+			if err != nil {
+				return nil, err
+			}
+			t.htmls[kind] = htmlTmpl
+		}
+	}
+
+	return t, nil
+}
+
+// Render отрисовывает уведомление по виду из data.Kind
+func (t *Templates) Render(data Data) (Message, error) {
+	subjectTmpl, ok := t.subjects[data.Kind]
+	if !ok {
+		return Message{}, fmt.Errorf("no template for notification kind %s", data.Kind)
+	}
+
+	var subject, body bytes.Buffer
+	if err := subjectTmpl.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := t.bodies[data.Kind].Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+
+	// Тема письма - одна строка
+	msg := Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Body:    strings.TrimSpace(body.String()),
+	}
+
+	if htmlTmpl, ok := t.htmls[data.Kind]; ok {
+		var html bytes.Buffer
+		if err := htmlTmpl.Execute(&html, data); err != nil {
+			return Message{}, err
+		}
+		msg.HTML = strings.TrimSpace(html.String())
+	}
+
+	return msg, nil
+}
+
+func readOverride(dir, name, fallback string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fallback, nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"golang.org/x/time/rate"
+)
+
+// Клиенты, не делавшие запросов дольше, удаляются из памяти
+const idleTTL = 10 * time.Minute
+
+// Limit - параметры token bucket: пополнение в секунду и размер корзины
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimit разбирает строку вида "<запросов в секунду>:<burst>", например "5:10".
+// Пустая строка или "0" - лимит выключен.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return Limit{}, nil
+	}
+
+	rateStr, burstStr, ok := strings.Cut(value, ":")
+	r, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateStr)
+	}
+
+	burst := max(int(r), 1)
+	if ok {
+		burst, err = strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstStr)
+		}
+	}
+
+	return Limit{Rate: r, Burst: burst}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ClientKey - ключ клиента: проверенный вызывающий, без него - IP.
+// Ключ по непроверенному токену не годится: случайные токены обходили бы
+// лимит и раздували таблицу клиентов.
+func ClientKey(identity *domain.Identity, ip string) string {
+	switch {
+	case identity == nil:
+		return "ip:" + ip
+	case identity.TokenID != "":
+		return "token:" + identity.TokenID
+	default:
+		return "subject:" + string(identity.Role) + ":" + identity.Subject
+	}
+}
+
+// Limiter - набор token bucket по ключу клиента (ClientKey)
+type Limiter struct {
+	limit Limit
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:     limit,
+		clients:   make(map[string]*client),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow списывает токен клиента. Если токенов нет, возвращает,
+// через сколько можно повторить запрос.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	l.sweep(now)
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(rate.Limit(l.limit.Rate), l.limit.Burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	l.mu.Unlock()
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep удаляет давно неактивных клиентов, вызывается под мьютексом
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > idleTTL {
+			delete(l.clients, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,20 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+)
+
+// Notifier доставляет напоминание ревьюверу. Ошибка означает, что напоминание
+// не доставлено и планировщик повторит его позже.
+type Notifier interface {
+	Notify(ctx context.Context, reminder domain.ReviewReminder) error
+}
+
+// NotifierFunc позволяет использовать функцию как Notifier
+type NotifierFunc func(ctx context.Context, reminder domain.ReviewReminder) error
+
+func (f NotifierFunc) Notify(ctx context.Context, reminder domain.ReviewReminder) error {
+	return f(ctx, reminder)
+}
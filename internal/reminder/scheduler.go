@@ -0,0 +1,178 @@
+package reminder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+const (
+	// Время, на которое напоминание захватывается одним экземпляром сервиса;
+	// недоставленное напоминание повторяется после его истечения
+	claimLease = 5 * time.Minute
+)
+
+// Scheduler периодически ищет зависшие открытые PR и напоминает о них ревьюверам
+// не чаще одного раза за repeat. Возраст PR считается в рабочем времени ревьювера.
+type Scheduler struct {
+	repo      repository.ReminderRepository
+	schedules repository.ScheduleRepository
+	notifier  Notifier
+	interval  time.Duration
+	threshold time.Duration
+	repeat    time.Duration
+
+	mu     sync.Mutex
+	status domain.ReminderSchedulerStatus
+}
+
+func NewScheduler(repo repository.ReminderRepository, schedules repository.ScheduleRepository, notifier Notifier, interval, threshold, repeat time.Duration) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		schedules: schedules,
+		notifier:  notifier,
+		interval:  interval,
+		threshold: threshold,
+		repeat:    repeat,
+		status: domain.ReminderSchedulerStatus{
+			CheckIntervalSeconds:    int64(interval / time.Second),
+			DefaultThresholdMinutes: int64(threshold / time.Minute),
+			RepeatIntervalMinutes:   int64(repeat / time.Minute),
+		},
+	}
+}
+
+// Run блокируется до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	s.setRunning(true)
+	defer s.setRunning(false)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.remind(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status возвращает состояние планировщика для /reminders/status
+func (s *Scheduler) Status() domain.ReminderSchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// remind отправляет напоминания по PR, зависшим дольше порога в рабочем времени
+// ревьювера, и только в его рабочие часы
+func (s *Scheduler) remind(ctx context.Context) {
+	var sent, failed int
+	var runErr error
+
+	due, err := s.repo.ListDue(ctx, s.threshold, s.repeat)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("reminder lookup failed", "error", err)
+			s.finishRun(0, 0, err)
+		}
+		return
+	}
+
+	calendars := businesstime.NewResolver(s.schedules)
+	now := time.Now()
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		reminder := d.Reminder
+		calendar, err := calendars.For(ctx, reminder.TeamName, reminder.ReviewerID)
+		if err != nil {
+			logger.FromContext(ctx).Error("reminder calendar lookup failed", "pr_id", reminder.PullRequestID, "error", err)
+			runErr = err
+			continue
+		}
+
+		threshold := s.threshold
+		if d.ThresholdMinutes != nil {
+			threshold = time.Duration(*d.ThresholdMinutes) * time.Minute
+		}
+		if !calendar.IsWorkingTime(now) || calendar.Elapsed(reminder.OpenedAt, now) < threshold {
+			continue
+		}
+
+		count, ok, err := s.repo.Claim(ctx, reminder.PullRequestID, reminder.ReviewerID, s.repeat, claimLease)
+		if err != nil {
+			logger.FromContext(ctx).Error("reminder claim failed", "pr_id", reminder.PullRequestID, "error", err)
+			runErr = err
+			continue
+		}
+		if !ok {
+			continue
+		}
+		reminder.Count = count
+
+		if err := s.notify(ctx, reminder); err != nil {
+			failed++
+			runErr = err
+		} else {
+			sent++
+		}
+	}
+
+	s.finishRun(sent, failed, runErr)
+}
+
+func (s *Scheduler) finishRun(sent, failed int, runErr error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.LastRunAt = &now
+	s.status.LastRunSent = sent
+	s.status.LastRunFailed = failed
+	s.status.SentTotal += int64(sent)
+	s.status.FailedTotal += int64(failed)
+	s.status.LastError = ""
+	if runErr != nil {
+		s.status.LastError = runErr.Error()
+	}
+}
+
+func (s *Scheduler) notify(ctx context.Context, reminder domain.ReviewReminder) error {
+	if err := s.notifier.Notify(ctx, reminder); err != nil {
+		metrics.IncReminder("failed")
+		logger.FromContext(ctx).Warn("review reminder failed",
+			"pr_id", reminder.PullRequestID,
+			"reviewer_id", reminder.ReviewerID,
+			"error", err,
+		)
+		if err := s.repo.MarkFailed(ctx, reminder.PullRequestID, reminder.ReviewerID, err.Error()); err != nil {
+			logger.FromContext(ctx).Error("reminder mark failed failed", "pr_id", reminder.PullRequestID, "error", err)
+		}
+		return err
+	}
+
+	metrics.IncReminder("sent")
+	// Напоминание уже доставлено: без отметки оно повторится после истечения захвата
+	if err := s.repo.MarkSent(ctx, reminder.PullRequestID, reminder.ReviewerID); err != nil {
+		logger.FromContext(ctx).Error("reminder mark sent failed", "pr_id", reminder.PullRequestID, "error", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) setRunning(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Running = running
+}
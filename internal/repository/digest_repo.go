@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DigestRepository interface {
+	Get(ctx context.Context, userID string) (*domain.DigestSubscription, error)
+	Set(ctx context.Context, sub *domain.DigestSubscription) error
+	Delete(ctx context.Context, userID string) error
+	ListEnabled(ctx context.Context) ([]domain.DigestSubscription, error)
+	MarkSent(ctx context.Context, userID, date string) error
+}
+
+type DigestRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDigestRepo(pool *pgxpool.Pool) DigestRepository {
+	return &DigestRepo{pool: pool}
+}
+
+const digestColumns = `d.user_id, d.enabled, d.send_at, d.timezone, d.days, to_char(d.last_sent_on, 'YYYY-MM-DD'), d.updated_at`
+
+func (r *DigestRepo) Get(ctx context.Context, userID string) (*domain.DigestSubscription, error) {
+	var sub domain.DigestSubscription
+	err := scanDigest(r.pool.QueryRow(ctx,
+		`SELECT `+digestColumns+` FROM review_digests d WHERE d.user_id = $1`,
+		userID,
+	), &sub)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// Set создаёт или заменяет подписку, дата последней сводки сохраняется
+func (r *DigestRepo) Set(ctx context.Context, sub *domain.DigestSubscription) error {
+	return scanDigest(r.pool.QueryRow(ctx,
+		`INSERT INTO review_digests AS d (user_id, enabled, send_at, timezone, days)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id)
+		 DO UPDATE SET enabled = EXCLUDED.enabled, send_at = EXCLUDED.send_at, timezone = EXCLUDED.timezone,
+		     days = EXCLUDED.days, updated_at = now()
+		 RETURNING `+digestColumns,
+		sub.UserID,
+		sub.Enabled,
+		sub.SendAt,
+		sub.Timezone,
+		sub.Days,
+	), sub)
+}
+
+func (r *DigestRepo) Delete(ctx context.Context, userID string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM review_digests WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListEnabled возвращает включённые подписки активных пользователей
+func (r *DigestRepo) ListEnabled(ctx context.Context) ([]domain.DigestSubscription, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+digestColumns+`
+		 FROM review_digests d
+		 JOIN users u ON u.id = d.user_id AND u.is_active
+		 WHERE d.enabled
+		 ORDER BY d.user_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []domain.DigestSubscription{}
+	for rows.Next() {
+		var sub domain.DigestSubscription
+		if err := scanDigest(rows, &sub); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+// MarkSent запоминает локальную дату отправленной сводки
+func (r *DigestRepo) MarkSent(ctx context.Context, userID, date string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE review_digests SET last_sent_on = $2::date WHERE user_id = $1`,
+		userID,
+		date,
+	)
+	return err
+}
+
+func scanDigest(row pgx.Row, sub *domain.DigestSubscription) error {
+	return row.Scan(&sub.UserID, &sub.Enabled, &sub.SendAt, &sub.Timezone, &sub.Days, &sub.LastSentOn, &sub.UpdatedAt)
+}
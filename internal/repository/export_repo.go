@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExportRepository читает выгрузки построчно: строки передаются в fn по мере
+// получения из базы и не накапливаются в памяти. Ошибка fn прерывает выгрузку.
+type ExportRepository interface {
+	StreamPullRequests(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExport) error) error
+	StreamAssignments(ctx context.Context, filter domain.ExportFilter, fn func(domain.AssignmentExport) error) error
+}
+
+type ExportRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExportRepo(pool *pgxpool.Pool) ExportRepository {
+	return &ExportRepo{pool: pool}
+}
+
+func (r *ExportRepo) StreamPullRequests(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExport) error) error {
+	rows, err := r.pool.Query(ctx,
+		`SELECT p.id, p.pull_request_name, p.author_id, COALESCE(t.name, ''), p.status,
+		        ARRAY(SELECT rv.user_id::text FROM pr_reviewers rv WHERE rv.pr_id = p.id ORDER BY rv.user_id),
+		        p.created_at, p.merged_at
+		 FROM prs p
+		 LEFT JOIN teams t ON t.id = p.team_id
+		 WHERE ($1 = '' OR t.name = $1)
+		   AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+		   AND ($3::timestamptz IS NULL OR p.created_at < $3)
+		   AND ($4 = '' OR p.status = $4)
+		 ORDER BY p.created_at, p.id`,
+		filter.TeamName,
+		filter.From,
+		filter.To,
+		filter.Status,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pr domain.PullRequestExport
+		err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.TeamName,
+			&pr.Status,
+			&pr.Reviewers,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *ExportRepo) StreamAssignments(ctx context.Context, filter domain.ExportFilter, fn func(domain.AssignmentExport) error) error {
+	rows, err := r.pool.Query(ctx,
+		`SELECT p.id, p.pull_request_name, COALESCE(t.name, ''), p.status,
+		        rv.user_id, rv.assigned_at, rv.verdict, rv.verdict_at
+		 FROM pr_reviewers rv
+		 JOIN prs p ON p.id = rv.pr_id
+		 LEFT JOIN teams t ON t.id = p.team_id
+		 WHERE ($1 = '' OR t.name = $1)
+		   AND ($2::timestamptz IS NULL OR rv.assigned_at >= $2)
+		   AND ($3::timestamptz IS NULL OR rv.assigned_at < $3)
+		   AND ($4 = '' OR p.status = $4)
+		 ORDER BY rv.assigned_at, p.id, rv.user_id`,
+		filter.TeamName,
+		filter.From,
+		filter.To,
+		filter.Status,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.AssignmentExport
+		err := rows.Scan(
+			&a.PullRequestID,
+			&a.PullRequestName,
+			&a.TeamName,
+			&a.Status,
+			&a.ReviewerID,
+			&a.AssignedAt,
+			&a.Verdict,
+			&a.VerdictAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
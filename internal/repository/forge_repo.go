@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ForgeRepository interface {
+	SaveUser(ctx context.Context, user *domain.ForgeUser) error
+	ListUsers(ctx context.Context, forge domain.Forge) ([]domain.ForgeUser, error)
+	GetUserID(ctx context.Context, forge domain.Forge, username string) (string, error)
+	GetPullRequest(ctx context.Context, forge domain.Forge, repository string, number int64) (*domain.ForgePullRequest, error)
+	GetPullRequestByID(ctx context.Context, prID string) (*domain.ForgePullRequest, error)
+	GetUsersByUserIDs(ctx context.Context, forge domain.Forge, userIDs []string) ([]domain.ForgeUser, error)
+	RecordSyncFailure(ctx context.Context, failure *domain.ForgeSyncFailure) error
+	ListSyncFailures(ctx context.Context) ([]domain.ForgeSyncFailure, error)
+}
+
+type ForgeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewForgeRepo(pool *pgxpool.Pool) ForgeRepository {
+	return &ForgeRepo{pool: pool}
+}
+
+func (r *ForgeRepo) SaveUser(ctx context.Context, user *domain.ForgeUser) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO forge_users (forge, forge_username, forge_user_id, user_id)
+		 VALUES ($1, $2, NULLIF($3, 0), $4)
+		 ON CONFLICT (forge, forge_username)
+		 DO UPDATE SET forge_user_id = EXCLUDED.forge_user_id, user_id = EXCLUDED.user_id`,
+		user.Forge,
+		user.Username,
+		user.ForgeUserID,
+		user.UserID,
+	)
+	return err
+}
+
+func (r *ForgeRepo) ListUsers(ctx context.Context, forge domain.Forge) ([]domain.ForgeUser, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT forge, forge_username, COALESCE(forge_user_id, 0), user_id
+		 FROM forge_users
+		 WHERE $1 = '' OR forge = $1
+		 ORDER BY forge, forge_username`,
+		forge,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.ForgeUser{}
+	for rows.Next() {
+		var user domain.ForgeUser
+		err := rows.Scan(&user.Forge, &user.Username, &user.ForgeUserID, &user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *ForgeRepo) GetUserID(ctx context.Context, forge domain.Forge, username string) (string, error) {
+	var userID string
+	err := r.pool.QueryRow(ctx,
+		`SELECT user_id FROM forge_users WHERE forge = $1 AND forge_username = $2`,
+		forge,
+		username,
+	).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+func (r *ForgeRepo) GetPullRequest(ctx context.Context, forge domain.Forge, repository string, number int64) (*domain.ForgePullRequest, error) {
+	link := domain.ForgePullRequest{
+		Forge:      forge,
+		Repository: repository,
+		Number:     number,
+	}
+	err := r.pool.QueryRow(ctx,
+		`SELECT pr_id, url FROM forge_pull_requests WHERE forge = $1 AND repository = $2 AND number = $3`,
+		forge,
+		repository,
+		number,
+	).Scan(&link.PullRequestID, &link.URL)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ForgeRepo) GetPullRequestByID(ctx context.Context, prID string) (*domain.ForgePullRequest, error) {
+	var link domain.ForgePullRequest
+	err := r.pool.QueryRow(ctx,
+		`SELECT forge, repository, number, pr_id, url FROM forge_pull_requests WHERE pr_id = $1`,
+		prID,
+	).Scan(&link.Forge, &link.Repository, &link.Number, &link.PullRequestID, &link.URL)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ForgeRepo) GetUsersByUserIDs(ctx context.Context, forge domain.Forge, userIDs []string) ([]domain.ForgeUser, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT forge, forge_username, COALESCE(forge_user_id, 0), user_id
+		 FROM forge_users
+		 WHERE forge = $1 AND user_id::text = ANY($2)`,
+		forge,
+		userIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.ForgeUser
+	for rows.Next() {
+		var user domain.ForgeUser
+		err := rows.Scan(&user.Forge, &user.Username, &user.ForgeUserID, &user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *ForgeRepo) RecordSyncFailure(ctx context.Context, failure *domain.ForgeSyncFailure) error {
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO forge_sync_failures (pr_id, forge, repository, number, event_id, error)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		failure.PullRequestID,
+		failure.Forge,
+		failure.Repository,
+		failure.Number,
+		failure.EventID,
+		failure.Error,
+	).Scan(&failure.ID, &failure.CreatedAt)
+}
+
+func (r *ForgeRepo) ListSyncFailures(ctx context.Context) ([]domain.ForgeSyncFailure, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, pr_id, forge, repository, number, event_id, error, created_at
+		 FROM forge_sync_failures
+		 ORDER BY id DESC
+		 LIMIT 500`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	failures := []domain.ForgeSyncFailure{}
+	for rows.Next() {
+		var f domain.ForgeSyncFailure
+		err := rows.Scan(&f.ID, &f.PullRequestID, &f.Forge, &f.Repository, &f.Number, &f.EventID, &f.Error, &f.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
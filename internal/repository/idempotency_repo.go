@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository interface {
+	Acquire(ctx context.Context, record *domain.IdempotencyRecord, lockTimeout time.Duration) (existing *domain.IdempotencyRecord, err error)
+	Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	Purge(ctx context.Context) (int64, error)
+}
+
+type IdempotencyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepo(pool *pgxpool.Pool) IdempotencyRepository {
+	return &IdempotencyRepo{pool: pool}
+}
+
+// Acquire занимает ключ под новый запрос. Если ключ уже занят, возвращает
+// существующую запись. Истёкшие записи и запросы, зависшие дольше lockTimeout
+// (например, из-за падения экземпляра), перезанимаются.
+func (r *IdempotencyRepo) Acquire(ctx context.Context, record *domain.IdempotencyRecord, lockTimeout time.Duration) (*domain.IdempotencyRecord, error) {
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO idempotency_keys (scope, key, request_hash, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (scope, key) DO UPDATE
+		 SET request_hash = EXCLUDED.request_hash,
+		     status_code = NULL,
+		     content_type = NULL,
+		     response_body = NULL,
+		     created_at = now(),
+		     expires_at = EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at < now()
+		    OR (idempotency_keys.status_code IS NULL
+		        AND idempotency_keys.created_at < now() - make_interval(secs => $5))
+		 RETURNING created_at`,
+		record.Scope,
+		record.Key,
+		record.RequestHash,
+		record.ExpiresAt,
+		lockTimeout.Seconds(),
+	).Scan(&record.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	existing := domain.IdempotencyRecord{Scope: record.Scope, Key: record.Key}
+	var statusCode *int
+	var contentType *string
+	err = r.pool.QueryRow(ctx,
+		`SELECT request_hash, status_code, content_type, response_body, created_at, expires_at
+		 FROM idempotency_keys
+		 WHERE scope = $1 AND key = $2`,
+		record.Scope,
+		record.Key,
+	).Scan(&existing.RequestHash, &statusCode, &contentType, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != nil {
+		existing.StatusCode = *statusCode
+	}
+	if contentType != nil {
+		existing.ContentType = *contentType
+	}
+
+	return &existing, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE idempotency_keys
+		 SET status_code = $3, content_type = $4, response_body = $5
+		 WHERE scope = $1 AND key = $2`,
+		scope,
+		key,
+		statusCode,
+		contentType,
+		body,
+	)
+	return err
+}
+
+// Release освобождает ключ, чтобы повтор запроса выполнился заново
+func (r *IdempotencyRepo) Release(ctx context.Context, scope, key string) error {
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code IS NULL`,
+		scope,
+		key,
+	)
+	return err
+}
+
+// Purge удаляет истёкшие записи
+func (r *IdempotencyRepo) Purge(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportRepository interface {
+	// ImportTeams сравнивает строки импорта с базой и в одной транзакции применяет
+	// разницу; при dryRun транзакция откатывается. Строки уже проверены: user_id
+	// уникальны и корректны.
+	ImportTeams(ctx context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportResult, error)
+}
+
+type ImportRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportRepo(pool *pgxpool.Pool) ImportRepository {
+	return &ImportRepo{pool: pool}
+}
+
+// importedUser - текущее состояние пользователя из импорта
+type importedUser struct {
+	username string
+	isActive bool
+	teams    []string
+}
+
+func (r *ImportRepo) ImportTeams(ctx context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userIDs := make([]string, 0, len(rows))
+	var teamNames []string
+	for _, row := range rows {
+		userIDs = append(userIDs, row.UserID)
+		if !slices.Contains(teamNames, row.TeamName) {
+			teamNames = append(teamNames, row.TeamName)
+		}
+	}
+
+	// Блокируем существующих пользователей, чтобы разница не устарела до коммита
+	existing := map[string]*importedUser{}
+	userRows, err := tx.Query(ctx,
+		`SELECT u.id::text, u.username, u.is_active, t.name
+		 FROM users u
+		 LEFT JOIN team_members tm ON tm.user_id = u.id
+		 LEFT JOIN teams t ON t.id = tm.team_id
+		 WHERE u.id = ANY($1::uuid[])
+		 ORDER BY u.id, t.name
+		 FOR UPDATE OF u`,
+		userIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for userRows.Next() {
+		var id, username string
+		var isActive bool
+		var teamName *string
+		if err := userRows.Scan(&id, &username, &isActive, &teamName); err != nil {
+			userRows.Close()
+			return nil, err
+		}
+		user, ok := existing[id]
+		if !ok {
+			user = &importedUser{username: username, isActive: isActive}
+			existing[id] = user
+		}
+		if teamName != nil {
+			user.teams = append(user.teams, *teamName)
+		}
+	}
+	userRows.Close()
+	if err = userRows.Err(); err != nil {
+		return nil, err
+	}
+
+	teamIDs := map[string]string{}
+	teamRows, err := tx.Query(ctx, `SELECT name, id FROM teams WHERE name = ANY($1)`, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	for teamRows.Next() {
+		var name, id string
+		if err := teamRows.Scan(&name, &id); err != nil {
+			teamRows.Close()
+			return nil, err
+		}
+		teamIDs[name] = id
+	}
+	teamRows.Close()
+	if err = teamRows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.ImportResult{
+		DryRun:       dryRun,
+		TeamsCreated: []string{},
+		Created:      []domain.ImportChange{},
+		Updated:      []domain.ImportChange{},
+		Moved:        []domain.ImportChange{},
+		Deactivated:  []domain.ImportChange{},
+	}
+
+	for _, name := range teamNames {
+		if _, ok := teamIDs[name]; ok {
+			continue
+		}
+		result.TeamsCreated = append(result.TeamsCreated, name)
+		if dryRun {
+			continue
+		}
+		var id string
+		if err := tx.QueryRow(ctx, `INSERT INTO teams (name) VALUES ($1) RETURNING id`, name).Scan(&id); err != nil {
+			return nil, err
+		}
+		teamIDs[name] = id
+	}
+
+	for _, row := range rows {
+		change := domain.ImportChange{
+			UserID:   row.UserID,
+			Username: row.Username,
+			TeamName: row.TeamName,
+		}
+
+		user, ok := existing[row.UserID]
+		if !ok {
+			result.Created = append(result.Created, change)
+			if dryRun {
+				continue
+			}
+			_, err := tx.Exec(ctx,
+				`INSERT INTO users (id, username, is_active) VALUES ($1, $2, $3)`,
+				row.UserID, row.Username, row.IsActive,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)`,
+				teamIDs[row.TeamName], row.UserID,
+			); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		renamed := user.username != row.Username
+		activated := !user.isActive && row.IsActive
+		deactivated := user.isActive && !row.IsActive
+		moved := len(user.teams) != 1 || user.teams[0] != row.TeamName
+
+		if renamed {
+			change.OldUsername = user.username
+		}
+		if renamed || activated {
+			result.Updated = append(result.Updated, change)
+		}
+		if deactivated {
+			result.Deactivated = append(result.Deactivated, change)
+		}
+		if moved {
+			// У пользователя без команды FromTeams пуст
+			movedChange := change
+			movedChange.FromTeams = user.teams
+			result.Moved = append(result.Moved, movedChange)
+		}
+		if !renamed && !activated && !deactivated && !moved {
+			result.Unchanged++
+		}
+
+		if dryRun {
+			continue
+		}
+
+		if renamed || activated || deactivated {
+			_, err := tx.Exec(ctx,
+				`UPDATE users SET username = $2, is_active = $3 WHERE id = $1`,
+				row.UserID, row.Username, row.IsActive,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if moved {
+			teamID := teamIDs[row.TeamName]
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM team_members WHERE user_id = $1 AND team_id <> $2`,
+				row.UserID, teamID,
+			); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO team_members (team_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				teamID, row.UserID,
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		// Те же события, что при /users/setIsActive
+		if activated {
+			err = insertEvent(ctx, tx, domain.EventUserActivated, row.UserID, domain.UserActivatedPayload{
+				UserID:   row.UserID,
+				TeamName: row.TeamName,
+			})
+		} else if deactivated {
+			err = insertEvent(ctx, tx, domain.EventUserDeactivated, row.UserID, domain.UserDeactivatedPayload{
+				UserID:   row.UserID,
+				TeamName: row.TeamName,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
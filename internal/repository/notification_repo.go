@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationRepository interface {
+	SetPreference(ctx context.Context, pref *domain.NotificationPreference) error
+	DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error
+	ListPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error)
+	Enqueue(ctx context.Context, deliveries []domain.NotificationDelivery) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.NotificationDelivery, error)
+	MarkSent(ctx context.Context, deliveryID int64) error
+	MarkFailed(ctx context.Context, deliveryID int64, errMsg string, nextAttemptAt *time.Time) error
+	ListDeliveries(ctx context.Context, userID string, status domain.NotificationStatus, limit int) ([]domain.NotificationDelivery, error)
+}
+
+type NotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepo(pool *pgxpool.Pool) NotificationRepository {
+	return &NotificationRepo{pool: pool}
+}
+
+// SetPreference создаёт или заменяет канал пользователя
+func (r *NotificationRepo) SetPreference(ctx context.Context, pref *domain.NotificationPreference) error {
+	var kinds []domain.NotificationKind
+	if len(pref.Kinds) > 0 {
+		kinds = pref.Kinds
+	}
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO notification_preferences (user_id, channel, address, enabled, kinds)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id, channel)
+		 DO UPDATE SET address = EXCLUDED.address, enabled = EXCLUDED.enabled, kinds = EXCLUDED.kinds, updated_at = now()
+		 RETURNING updated_at`,
+		pref.UserID,
+		pref.Channel,
+		pref.Address,
+		pref.Enabled,
+		kinds,
+	).Scan(&pref.UpdatedAt)
+}
+
+func (r *NotificationRepo) DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM notification_preferences WHERE user_id = $1 AND channel = $2`,
+		userID,
+		channel,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepo) ListPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT user_id, channel, address, enabled, kinds, updated_at
+		 FROM notification_preferences
+		 WHERE user_id = $1
+		 ORDER BY channel`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := []domain.NotificationPreference{}
+	for rows.Next() {
+		var pref domain.NotificationPreference
+		err := rows.Scan(&pref.UserID, &pref.Channel, &pref.Address, &pref.Enabled, &pref.Kinds, &pref.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, pref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// Enqueue ставит уведомления в очередь. Повторная постановка того же уведомления
+// (тот же dedup_key, пользователь и канал) не создаёт дублей.
+func (r *NotificationRepo) Enqueue(ctx context.Context, deliveries []domain.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(
+			`INSERT INTO notification_deliveries (user_id, channel, address, kind, dedup_key, subject, body, html_body)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+			 ON CONFLICT (dedup_key, user_id, channel) DO NOTHING`,
+			d.UserID,
+			d.Channel,
+			d.Address,
+			d.Kind,
+			d.DedupKey,
+			d.Subject,
+			d.Body,
+			d.HTML,
+		)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+// Claim захватывает готовые к отправке уведомления, откладывая их на время lease,
+// чтобы другой экземпляр сервиса не отправил их параллельно
+func (r *NotificationRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.NotificationDelivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`UPDATE notification_deliveries
+		 SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		 WHERE id IN (
+		     SELECT id FROM notification_deliveries
+		     WHERE status = 'PENDING' AND next_attempt_at <= now()
+		     ORDER BY next_attempt_at, id
+		     LIMIT $1
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING `+notificationColumns,
+		limit,
+		lease.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectNotifications(rows)
+}
+
+func (r *NotificationRepo) MarkSent(ctx context.Context, deliveryID int64) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE notification_deliveries
+		 SET status = 'SENT', attempts = attempts + 1, last_error = NULL, sent_at = now()
+		 WHERE id = $1`,
+		deliveryID,
+	)
+	return err
+}
+
+// MarkFailed фиксирует неудачную попытку. Без nextAttemptAt уведомление больше не отправляется.
+func (r *NotificationRepo) MarkFailed(ctx context.Context, deliveryID int64, errMsg string, nextAttemptAt *time.Time) error {
+	status := domain.NotificationPending
+	if nextAttemptAt == nil {
+		status = domain.NotificationFailed
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE notification_deliveries
+		 SET status = $2, attempts = attempts + 1, last_error = $3, next_attempt_at = COALESCE($4, next_attempt_at)
+		 WHERE id = $1`,
+		deliveryID,
+		status,
+		errMsg,
+		nextAttemptAt,
+	)
+	return err
+}
+
+// ListDeliveries возвращает журнал доставки, новые записи первыми
+func (r *NotificationRepo) ListDeliveries(ctx context.Context, userID string, status domain.NotificationStatus, limit int) ([]domain.NotificationDelivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+notificationColumns+`
+		 FROM notification_deliveries
+		 WHERE ($1 = '' OR user_id::text = $1) AND ($2 = '' OR status = $2)
+		 ORDER BY id DESC
+		 LIMIT $3`,
+		userID,
+		status,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectNotifications(rows)
+}
+
+const notificationColumns = `id, user_id, channel, address, kind, dedup_key, subject, body, COALESCE(html_body, ''),
+	status, attempts, COALESCE(last_error, ''), created_at, sent_at`
+
+func collectNotifications(rows pgx.Rows) ([]domain.NotificationDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []domain.NotificationDelivery{}
+	for rows.Next() {
+		var d domain.NotificationDelivery
+		err := rows.Scan(
+			&d.DeliveryID,
+			&d.UserID,
+			&d.Channel,
+			&d.Address,
+			&d.Kind,
+			&d.DedupKey,
+			&d.Subject,
+			&d.Body,
+			&d.HTML,
+			&d.Status,
+			&d.Attempts,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.SentAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ключ advisory-блокировки, под которой выдаются номера доставки
+const dispatchSeqLock = 0x6f7574626f78 // "outbox"
+
+type OutboxRepository interface {
+	// Claim захватывает готовые к доставке события на время lease, откладывая
+	// их следующую попытку. Обработчики вызываются вне транзакции, а захват
+	// упавшего экземпляра истекает сам.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.Event, error)
+	// MarkDispatched помечает событие доставленным всем подписчикам и выдаёт ему
+	// номер доставки. Номера фиксируются в порядке выдачи, поэтому читатель,
+	// увидевший номер n, уже видит и все меньшие.
+	MarkDispatched(ctx context.Context, id int64, delivered []string) (seq int64, err error)
+	// MarkFailed запоминает подписчиков, уже получивших событие, и откладывает
+	// повтор до nextAttemptAt. Без nextAttemptAt событие становится мёртвым.
+	MarkFailed(ctx context.Context, id int64, delivered []string, errMsg string, nextAttemptAt *time.Time) error
+	// ListDispatched возвращает доставленные события с номером доставки больше afterSeq
+	ListDispatched(ctx context.Context, afterSeq int64, limit int) ([]domain.Event, error)
+}
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) OutboxRepository {
+	return &OutboxRepo{pool: pool}
+}
+
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.Event, error) {
+	// SKIP LOCKED позволяет нескольким экземплярам сервиса разбирать outbox параллельно
+	rows, err := r.pool.Query(ctx,
+		`UPDATE outbox_events
+		 SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		 WHERE id IN (
+		     SELECT id FROM outbox_events
+		     WHERE dispatched_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now()
+		     ORDER BY id
+		     LIMIT $1
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, event_type, aggregate_id, payload, created_at, attempts, delivered_to`,
+		limit,
+		lease.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.AggregateID,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Attempts,
+			&event.DeliveredTo,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(events, func(a, b domain.Event) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (r *OutboxRepo) MarkDispatched(ctx context.Context, id int64, delivered []string) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Номер выдаётся под блокировкой до конца транзакции: иначе номер n+1 мог бы
+	// зафиксироваться раньше n, и возобновивший поток клиент пропустил бы n
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, dispatchSeqLock); err != nil {
+		return 0, err
+	}
+
+	var seq int64
+	err = tx.QueryRow(ctx,
+		`UPDATE outbox_events
+		 SET dispatched_at = now(),
+		     dispatch_seq = nextval('outbox_dispatch_seq'),
+		     delivered_to = ARRAY(SELECT DISTINCT unnest(delivered_to || $2::text[])),
+		     last_error = NULL
+		 WHERE id = $1
+		 RETURNING dispatch_seq`,
+		id,
+		delivered,
+	).Scan(&seq)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, err
+	}
+
+	return seq, tx.Commit(ctx)
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, delivered []string, errMsg string, nextAttemptAt *time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE outbox_events
+		 SET attempts = attempts + 1,
+		     delivered_to = ARRAY(SELECT DISTINCT unnest(delivered_to || $2::text[])),
+		     last_error = $3,
+		     next_attempt_at = COALESCE($4, next_attempt_at),
+		     dead_at = CASE WHEN $4::timestamptz IS NULL THEN now() END
+		 WHERE id = $1`,
+		id,
+		delivered,
+		errMsg,
+		nextAttemptAt,
+	)
+	return err
+}
+
+func (r *OutboxRepo) ListDispatched(ctx context.Context, afterSeq int64, limit int) ([]domain.Event, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, event_type, aggregate_id, payload, created_at, dispatch_seq
+		 FROM outbox_events
+		 WHERE dispatch_seq > $1
+		 ORDER BY dispatch_seq
+		 LIMIT $2`,
+		afterSeq,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.Event{}
+	for rows.Next() {
+		var event domain.Event
+		err := rows.Scan(&event.ID, &event.Type, &event.AggregateID, &event.Payload, &event.CreatedAt, &event.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// insertEvent записывает доменное событие в outbox в рамках транзакции изменения
+func insertEvent(ctx context.Context, tx pgx.Tx, eventType domain.EventType, aggregateID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO outbox_events (event_type, aggregate_id, payload) VALUES ($1, $2, $3)`,
+		eventType,
+		aggregateID,
+		data,
+	)
+	return err
+}
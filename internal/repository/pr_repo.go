@@ -6,15 +6,27 @@ import (
 	"time"
 
 	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PrRepository interface {
-	Create(ctx context.Context, pr *domain.PullRequestShort) error
-	Merge(ctx context.Context, prId string) error
-	Reassign(ctx context.Context, pullRequestId, oldUserId string) (newReviewerID string, err error)
+	Create(ctx context.Context, pr *domain.PullRequestShort, actor string) error
+	// CreateLinked создаёт PR вместе со связью с PR на GitHub/GitLab в одной транзакции.
+	// linked = false, если этот PR платформы уже связан (например, параллельной
+	// доставкой того же вебхука): тогда ничего не создаётся.
+	CreateLinked(ctx context.Context, pr *domain.PullRequestShort, link *domain.ForgePullRequest, actor string) (linked bool, err error)
+	Merge(ctx context.Context, prId, actor string) error
+	Reassign(ctx context.Context, pullRequestId, oldUserId, reason, actor string) (newReviewerID string, err error)
+	// ReassignOverdue переназначает просроченное ревью, только если под блокировкой PR
+	// ревьювер всё ещё без вердикта и назначен в review.AssignedAt.
+	// reassigned = false, если ревью изменилось после выборки: тогда ничего не меняется.
+	ReassignOverdue(ctx context.Context, review domain.OverdueReview, reason, actor string) (newReviewerID string, reassigned bool, err error)
 	GetByID(ctx context.Context, prID string) (*domain.PullRequest, error)
+	GetHistory(ctx context.Context, prID string) ([]domain.ReviewerHistoryEntry, error)
+	SetVerdict(ctx context.Context, prID, userID string, verdict domain.ReviewVerdict) error
+	ListOverdue(ctx context.Context) ([]domain.OverdueReview, error)
 }
 
 type PrRepo struct {
@@ -25,32 +37,80 @@ func NewPrRepo(pool *pgxpool.Pool) PrRepository {
 	return &PrRepo{pool: pool}
 }
 
-func (r *PrRepo) Create(ctx context.Context, pr *domain.PullRequestShort) error {
+func (r *PrRepo) Create(ctx context.Context, pr *domain.PullRequestShort, actor string) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	err = tx.QueryRow(ctx,
-		`INSERT INTO prs (pull_request_name, author_id, status, created_at) 
-		 VALUES ($1, $2, $3, $4) 
-		 RETURNING id`,
-		pr.PullRequestName,
+	if err := createPR(ctx, tx, pr, actor); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PrRepo) CreateLinked(ctx context.Context, pr *domain.PullRequestShort, link *domain.ForgePullRequest, actor string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := createPR(ctx, tx, pr, actor); err != nil {
+		return false, err
+	}
+
+	// Параллельная вставка той же связи дождётся коммита первой транзакции
+	// и ничего не вставит; тогда PR откатывается вместе с его событиями
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO forge_pull_requests (forge, repository, number, pr_id, url)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (forge, repository, number) DO NOTHING`,
+		link.Forge,
+		link.Repository,
+		link.Number,
+		pr.PullRequestID,
+		link.URL,
+	)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	link.PullRequestID = pr.PullRequestID
+
+	return true, tx.Commit(ctx)
+}
+
+// createPR создаёт PR с ревьюверами и событиями outbox в транзакции tx
+func createPR(ctx context.Context, tx pgx.Tx, pr *domain.PullRequestShort, actor string) error {
+	// Получаем команду автора, PR закрепляется за ней
+	var teamID, teamName string
+	err := tx.QueryRow(ctx,
+		`SELECT tm.team_id, t.name
+		 FROM team_members tm
+		 JOIN teams t ON t.id = tm.team_id
+		 WHERE tm.user_id = $1
+		 LIMIT 1`,
 		pr.AuthorID,
-		domain.PRStatusOpen,
-		time.Now(),
-	).Scan(&pr.PullRequestID)
+	).Scan(&teamID, &teamName)
 	if err != nil {
 		return err
 	}
 
-	// Получаем команду автора
-	var teamID string
 	err = tx.QueryRow(ctx,
-		`SELECT team_id FROM team_members WHERE user_id = $1 LIMIT 1`,
+		`INSERT INTO prs (pull_request_name, author_id, team_id, status, created_at) 
+		 VALUES ($1, $2, $3, $4, $5) 
+		 RETURNING id`,
+		pr.PullRequestName,
 		pr.AuthorID,
-	).Scan(&teamID)
+		teamID,
+		domain.PRStatusOpen,
+		time.Now(),
+	).Scan(&pr.PullRequestID)
 	if err != nil {
 		return err
 	}
@@ -86,6 +146,8 @@ func (r *PrRepo) Create(ctx context.Context, pr *domain.PullRequestShort) error
 		return err
 	}
 
+	logger.FromContext(ctx).Debug("reviewers selected", "team_id", teamID, "reviewers", reviewers)
+
 	// Назначаем ревьюверов
 	for _, reviewerID := range reviewers {
 		_, err = tx.Exec(ctx,
@@ -96,89 +158,170 @@ func (r *PrRepo) Create(ctx context.Context, pr *domain.PullRequestShort) error
 		if err != nil {
 			return err
 		}
+
+		err = insertHistory(ctx, tx, domain.ReviewerHistoryEntry{
+			PullRequestID: pr.PullRequestID,
+			Event:         domain.ReviewerEventAssigned,
+			UserID:        reviewerID,
+			Reason:        "assigned on PR creation",
+			Actor:         actor,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = insertEvent(ctx, tx, domain.EventReviewerAssigned, pr.PullRequestID, domain.ReviewerAssignedPayload{
+			PullRequestID: pr.PullRequestID,
+			TeamName:      teamName,
+			ReviewerID:    reviewerID,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	return tx.Commit(ctx)
+	err = insertEvent(ctx, tx, domain.EventPRCreated, pr.PullRequestID, domain.PRCreatedPayload{
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorID:        pr.AuthorID,
+		TeamName:        teamName,
+		Reviewers:       reviewers,
+	})
+	return err
 }
 
-func (r *PrRepo) Merge(ctx context.Context, prId string) error {
-	result, err := r.pool.Exec(ctx,
+func (r *PrRepo) Merge(ctx context.Context, prId, actor string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status, authorID, teamName string
+	err = tx.QueryRow(ctx,
+		`SELECT p.status, p.author_id, COALESCE(t.name, '')
+		 FROM prs p
+		 LEFT JOIN teams t ON p.team_id = t.id
+		 WHERE p.id = $1
+		 FOR UPDATE OF p`,
+		prId,
+	).Scan(&status, &authorID, &teamName)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("PR not found")
+		}
+		return err
+	}
+
+	// Повторный мердж ничего не меняет и не порождает событие
+	if status == string(domain.PRStatusMerged) {
+		return nil
+	}
+
+	mergedAt := time.Now()
+	_, err = tx.Exec(ctx,
 		`UPDATE prs 
 		 SET status = $1, merged_at = $2 
 		 WHERE id = $3`,
 		domain.PRStatusMerged,
-		time.Now(),
+		mergedAt,
 		prId,
 	)
 	if err != nil {
 		return err
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("PR not found or already merged")
+	err = insertEvent(ctx, tx, domain.EventPRMerged, prId, domain.PRMergedPayload{
+		PullRequestID: prId,
+		AuthorID:      authorID,
+		TeamName:      teamName,
+		MergedAt:      mergedAt,
+		MergedBy:      actor,
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
-func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string) (newReviewerID string, err error) {
+func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId, reason, actor string) (string, error) {
+	newReviewerID, _, err := r.reassign(ctx, pullRequestId, oldUserId, nil, reason, actor)
+	return newReviewerID, err
+}
+
+func (r *PrRepo) ReassignOverdue(ctx context.Context, review domain.OverdueReview, reason, actor string) (string, bool, error) {
+	return r.reassign(ctx, review.PullRequestID, review.ReviewerID, &review.AssignedAt, reason, actor)
+}
+
+// reassign заменяет ревьювера. Если задан overdueAssignedAt, ревью, изменившееся
+// после выборки просроченных (смердженный PR, вердикт, повторное назначение),
+// пропускается без ошибки.
+func (r *PrRepo) reassign(ctx context.Context, pullRequestId, oldUserId string, overdueAssignedAt *time.Time, reason, actor string) (newReviewerID string, reassigned bool, err error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer tx.Rollback(ctx)
 
-	// Проверяем, что PR не в статусе MERGED
-	var status string
+	// Проверяем, что PR не в статусе MERGED, и получаем его автора и команду.
+	// Блокировка PR не даёт параллельно переназначить одного ревьювера дважды
+	// (например, вручную и эскалацией по SLA)
+	var status, authorID, teamName string
+	var teamID *string
 	err = tx.QueryRow(ctx,
-		`SELECT status FROM prs WHERE id = $1`,
+		`SELECT p.status, p.author_id, p.team_id, COALESCE(t.name, '')
+		 FROM prs p
+		 LEFT JOIN teams t ON p.team_id = t.id
+		 WHERE p.id = $1
+		 FOR UPDATE OF p`,
 		pullRequestId,
-	).Scan(&status)
+	).Scan(&status, &authorID, &teamID, &teamName)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	if status == string(domain.PRStatusMerged) {
-		return "", fmt.Errorf("cannot reassign reviewers for merged PR")
+		if overdueAssignedAt != nil {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("cannot reassign reviewers for merged PR")
 	}
 
-	// Проверяем, что oldUserId является ревьювером этого PR
-	var exists bool
-	err = tx.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2)`,
-		pullRequestId,
-		oldUserId,
-	).Scan(&exists)
-	if err != nil {
-		return "", err
+	if teamID == nil {
+		return "", false, fmt.Errorf("PR is not in any team")
 	}
 
-	if !exists {
-		return "", fmt.Errorf("user is not a reviewer of this PR")
+	// Проверяем, что oldUserId является ревьювером этого PR. Для эскалации ещё и
+	// что он не вынес вердикт и не был переназначен заново после выборки:
+	// иначе удаление строки стёрло бы его вердикт
+	var exists bool
+	if overdueAssignedAt != nil {
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS(
+			   SELECT 1 FROM pr_reviewers
+			   WHERE pr_id = $1 AND user_id = $2 AND verdict IS NULL AND assigned_at = $3
+			 )`,
+			pullRequestId,
+			oldUserId,
+			*overdueAssignedAt,
+		).Scan(&exists)
+	} else {
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2)`,
+			pullRequestId,
+			oldUserId,
+		).Scan(&exists)
 	}
-
-	// Получаем команду заменяемого ревьювера
-	var teamID string
-	err = tx.QueryRow(ctx,
-		`SELECT team_id FROM team_members WHERE user_id = $1 LIMIT 1`,
-		oldUserId,
-	).Scan(&teamID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("user is not in any team")
-		}
-		return "", err
+		return "", false, err
 	}
 
-	// Получаем автора PR
-	var authorID string
-	err = tx.QueryRow(ctx,
-		`SELECT author_id FROM prs WHERE id = $1`,
-		pullRequestId,
-	).Scan(&authorID)
-	if err != nil {
-		return "", err
+	if !exists {
+		if overdueAssignedAt != nil {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("user is not a reviewer of this PR")
 	}
 
 	// Получаем текущих ревьюверов PR
@@ -187,7 +330,7 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 		pullRequestId,
 	)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	var currentReviewers []string
@@ -195,17 +338,17 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 		var reviewerID string
 		if err := rows.Scan(&reviewerID); err != nil {
 			rows.Close()
-			return "", err
+			return "", false, err
 		}
 		currentReviewers = append(currentReviewers, reviewerID)
 	}
 	rows.Close()
 
 	if err = rows.Err(); err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	// Находим случайного активного участника из команды, исключая автора и текущих ревьюверов
+	// Находим случайного активного участника из команды PR, исключая автора и текущих ревьюверов
 	query := `SELECT u.id 
 		 FROM users u
 		 JOIN team_members tm ON u.id = tm.user_id
@@ -213,7 +356,7 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 		   AND u.id != $2 
 		   AND u.is_active = true`
 
-	args := []interface{}{teamID, authorID}
+	args := []interface{}{*teamID, authorID}
 	for i, reviewerID := range currentReviewers {
 		query += fmt.Sprintf(" AND u.id != $%d", i+3)
 		args = append(args, reviewerID)
@@ -224,11 +367,13 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 	err = tx.QueryRow(ctx, query, args...).Scan(&newReviewerID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("no available reviewers in team")
+			return "", false, fmt.Errorf("no available reviewers in team")
 		}
-		return "", err
+		return "", false, err
 	}
 
+	logger.FromContext(ctx).Debug("replacement reviewer selected", "team_id", *teamID, "new_reviewer_id", newReviewerID)
+
 	// Удаляем старого ревьювера
 	_, err = tx.Exec(ctx,
 		`DELETE FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2`,
@@ -236,7 +381,7 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 		oldUserId,
 	)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	// Добавляем нового ревьювера
@@ -246,23 +391,63 @@ func (r *PrRepo) Reassign(ctx context.Context, pullRequestId, oldUserId string)
 		newReviewerID,
 	)
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+
+	// Сохраняем переназначение в истории, чтобы не терять, кто ревьюил PR
+	err = insertHistory(ctx, tx, domain.ReviewerHistoryEntry{
+		PullRequestID: pullRequestId,
+		Event:         domain.ReviewerEventReassignedFrom,
+		UserID:        oldUserId,
+		RelatedUserID: newReviewerID,
+		Reason:        reason,
+		Actor:         actor,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	err = insertHistory(ctx, tx, domain.ReviewerHistoryEntry{
+		PullRequestID: pullRequestId,
+		Event:         domain.ReviewerEventReassignedTo,
+		UserID:        newReviewerID,
+		RelatedUserID: oldUserId,
+		Reason:        reason,
+		Actor:         actor,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	err = insertEvent(ctx, tx, domain.EventReviewerReassigned, pullRequestId, domain.ReviewerReassignedPayload{
+		PullRequestID: pullRequestId,
+		TeamName:      teamName,
+		OldReviewerID: oldUserId,
+		NewReviewerID: newReviewerID,
+		Reason:        reason,
+		Actor:         actor,
+	})
+	if err != nil {
+		return "", false, err
 	}
 
-	return newReviewerID, tx.Commit(ctx)
+	return newReviewerID, true, tx.Commit(ctx)
 }
 
 func (r *PrRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	// Получаем основные данные PR
 	var pr domain.PullRequest
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, pull_request_name, author_id, status, created_at, merged_at
-		 FROM prs WHERE id = $1`,
+		`SELECT p.id, p.pull_request_name, p.author_id, COALESCE(t.name, ''), p.status, p.created_at, p.merged_at
+		 FROM prs p
+		 LEFT JOIN teams t ON p.team_id = t.id
+		 WHERE p.id = $1`,
 		prID,
 	).Scan(
 		&pr.PullRequestID,
 		&pr.PullRequestName,
 		&pr.AuthorID,
+		&pr.TeamName,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.MergedAt,
@@ -271,9 +456,9 @@ func (r *PrRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 		return nil, err
 	}
 
-	// Получаем список ревьюверов
+	// Получаем список ревьюверов и их вердикты
 	rows, err := r.pool.Query(ctx,
-		`SELECT user_id FROM pr_reviewers WHERE pr_id = $1`,
+		`SELECT user_id, verdict FROM pr_reviewers WHERE pr_id = $1`,
 		prID,
 	)
 	if err != nil {
@@ -284,10 +469,17 @@ func (r *PrRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 	var reviewers []string
 	for rows.Next() {
 		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
+		var verdict *domain.ReviewVerdict
+		if err := rows.Scan(&reviewerID, &verdict); err != nil {
 			return nil, err
 		}
 		reviewers = append(reviewers, reviewerID)
+		if verdict != nil {
+			if pr.Verdicts == nil {
+				pr.Verdicts = map[string]domain.ReviewVerdict{}
+			}
+			pr.Verdicts[reviewerID] = *verdict
+		}
 	}
 
 	if err = rows.Err(); err != nil {
@@ -297,3 +489,129 @@ func (r *PrRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 	pr.AssignedReviewers = reviewers
 	return &pr, nil
 }
+
+func (r *PrRepo) GetHistory(ctx context.Context, prID string) ([]domain.ReviewerHistoryEntry, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT pr_id, event, user_id, COALESCE(related_user_id::text, ''), COALESCE(reason, ''), COALESCE(actor, ''), created_at
+		 FROM pr_reviewer_history
+		 WHERE pr_id = $1
+		 ORDER BY created_at, id`,
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []domain.ReviewerHistoryEntry{}
+	for rows.Next() {
+		var entry domain.ReviewerHistoryEntry
+		err := rows.Scan(
+			&entry.PullRequestID,
+			&entry.Event,
+			&entry.UserID,
+			&entry.RelatedUserID,
+			&entry.Reason,
+			&entry.Actor,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// SetVerdict сохраняет вердикт ревьювера открытого PR
+func (r *PrRepo) SetVerdict(ctx context.Context, prID, userID string, verdict domain.ReviewVerdict) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT status FROM prs WHERE id = $1 FOR UPDATE`,
+		prID,
+	).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if status == string(domain.PRStatusMerged) {
+		return fmt.Errorf("cannot submit verdict for merged PR")
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE pr_reviewers SET verdict = $3, verdict_at = now() WHERE pr_id = $1 AND user_id = $2`,
+		prID,
+		userID,
+		verdict,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user is not a reviewer of this PR")
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListOverdue возвращает ревью открытых PR без вердикта, назначенные раньше,
+// чем SLA команды назад по часам. Рабочее время проверяет вызывающий.
+func (r *PrRepo) ListOverdue(ctx context.Context) ([]domain.OverdueReview, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT p.id, t.name, rv.user_id, rv.assigned_at, t.review_sla_minutes
+		 FROM pr_reviewers rv
+		 JOIN prs p ON p.id = rv.pr_id
+		 JOIN teams t ON t.id = p.team_id
+		 WHERE p.status = 'OPEN'
+		   AND rv.verdict IS NULL
+		   AND t.review_sla_minutes IS NOT NULL
+		   AND rv.assigned_at <= now() - t.review_sla_minutes * interval '1 minute'
+		 ORDER BY rv.assigned_at, p.id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []domain.OverdueReview{}
+	for rows.Next() {
+		var review domain.OverdueReview
+		err := rows.Scan(&review.PullRequestID, &review.TeamName, &review.ReviewerID, &review.AssignedAt, &review.ReviewSLAMinutes)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// insertHistory добавляет запись в историю назначений в рамках транзакции
+func insertHistory(ctx context.Context, tx pgx.Tx, entry domain.ReviewerHistoryEntry) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO pr_reviewer_history (pr_id, event, user_id, related_user_id, reason, actor)
+		 VALUES ($1, $2, $3, NULLIF($4, '')::uuid, NULLIF($5, ''), NULLIF($6, ''))`,
+		entry.PullRequestID,
+		entry.Event,
+		entry.UserID,
+		entry.RelatedUserID,
+		entry.Reason,
+		entry.Actor,
+	)
+	return err
+}
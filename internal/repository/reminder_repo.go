@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReminderRepository interface {
+	ListDue(ctx context.Context, threshold, repeat time.Duration) ([]DueReminder, error)
+	Claim(ctx context.Context, prID, reviewerID string, repeat, lease time.Duration) (count int, ok bool, err error)
+	MarkSent(ctx context.Context, prID, reviewerID string) error
+	MarkFailed(ctx context.Context, prID, reviewerID, errMsg string) error
+	ListOpen(ctx context.Context) ([]domain.ReminderRecord, error)
+	ListTeamThresholds(ctx context.Context) ([]domain.TeamReminderThreshold, error)
+	SetTeamThreshold(ctx context.Context, teamName string, minutes *int) error
+}
+
+type ReminderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepo(pool *pgxpool.Pool) ReminderRepository {
+	return &ReminderRepo{pool: pool}
+}
+
+// DueReminder - кандидат на напоминание вместе с порогом команды
+type DueReminder struct {
+	Reminder domain.ReviewReminder
+	// Порог команды в минутах, nil - порог из конфигурации
+	ThresholdMinutes *int
+}
+
+// ListDue возвращает активных ревьюверов без вердикта по открытым PR, которые
+// старше порога команды (threshold, если порог не задан) по часам, и по которым
+// не напоминали дольше repeat. Рабочее время проверяет планировщик.
+func (r *ReminderRepo) ListDue(ctx context.Context, threshold, repeat time.Duration) ([]DueReminder, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT p.id, p.pull_request_name, p.author_id, t.name, rv.user_id, p.created_at, t.reminder_after_minutes
+		 FROM prs p
+		 JOIN teams t ON t.id = p.team_id
+		 JOIN pr_reviewers rv ON rv.pr_id = p.id AND rv.verdict IS NULL
+		 JOIN users u ON u.id = rv.user_id AND u.is_active
+		 LEFT JOIN review_reminders rr ON rr.pr_id = rv.pr_id AND rr.user_id = rv.user_id
+		 WHERE p.status = 'OPEN'
+		   AND p.created_at <= now() - COALESCE(t.reminder_after_minutes * interval '1 minute', $1 * interval '1 millisecond')
+		   AND (rr.last_reminded_at IS NULL OR rr.last_reminded_at <= now() - $2 * interval '1 millisecond')
+		   AND (rr.claimed_until IS NULL OR rr.claimed_until <= now())
+		 ORDER BY p.created_at, p.id, rv.user_id`,
+		threshold.Milliseconds(),
+		repeat.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []DueReminder{}
+	for rows.Next() {
+		var d DueReminder
+		err := rows.Scan(
+			&d.Reminder.PullRequestID,
+			&d.Reminder.PullRequestName,
+			&d.Reminder.AuthorID,
+			&d.Reminder.TeamName,
+			&d.Reminder.ReviewerID,
+			&d.Reminder.OpenedAt,
+			&d.ThresholdMinutes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return due, nil
+}
+
+// Claim захватывает напоминание на время lease, чтобы другой экземпляр сервиса
+// не отправил его параллельно, и возвращает его номер. ok = false, если напоминание
+// уже захвачено или отправлено за последний repeat.
+func (r *ReminderRepo) Claim(ctx context.Context, prID, reviewerID string, repeat, lease time.Duration) (count int, ok bool, err error) {
+	err = r.pool.QueryRow(ctx,
+		`INSERT INTO review_reminders (pr_id, user_id, claimed_until)
+		 VALUES ($1, $2, now() + $4 * interval '1 millisecond')
+		 ON CONFLICT (pr_id, user_id) DO UPDATE SET claimed_until = EXCLUDED.claimed_until
+		 WHERE (review_reminders.claimed_until IS NULL OR review_reminders.claimed_until <= now())
+		   AND (review_reminders.last_reminded_at IS NULL OR review_reminders.last_reminded_at <= now() - $3 * interval '1 millisecond')
+		 RETURNING reminder_count + 1`,
+		prID,
+		reviewerID,
+		repeat.Milliseconds(),
+		lease.Milliseconds(),
+	).Scan(&count)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return count, true, nil
+}
+
+// MarkSent учитывает отправленное напоминание и снимает захват
+func (r *ReminderRepo) MarkSent(ctx context.Context, prID, reviewerID string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE review_reminders
+		 SET reminder_count = reminder_count + 1, last_reminded_at = now(), claimed_until = NULL, last_error = NULL
+		 WHERE pr_id = $1 AND user_id = $2`,
+		prID,
+		reviewerID,
+	)
+	return err
+}
+
+// MarkFailed сохраняет ошибку; захват остаётся, и напоминание повторится после его истечения
+func (r *ReminderRepo) MarkFailed(ctx context.Context, prID, reviewerID, errMsg string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE review_reminders SET last_error = $3 WHERE pr_id = $1 AND user_id = $2`,
+		prID,
+		reviewerID,
+		errMsg,
+	)
+	return err
+}
+
+// ListOpen возвращает учёт напоминаний текущим ревьюверам открытых PR
+func (r *ReminderRepo) ListOpen(ctx context.Context) ([]domain.ReminderRecord, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT rr.pr_id, rr.user_id, COALESCE(t.name, ''), rr.reminder_count, rr.last_reminded_at, COALESCE(rr.last_error, '')
+		 FROM review_reminders rr
+		 JOIN prs p ON p.id = rr.pr_id AND p.status = 'OPEN'
+		 JOIN pr_reviewers rv ON rv.pr_id = rr.pr_id AND rv.user_id = rr.user_id
+		 LEFT JOIN teams t ON t.id = p.team_id
+		 ORDER BY rr.reminder_count DESC, p.created_at, rr.pr_id, rr.user_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []domain.ReminderRecord{}
+	for rows.Next() {
+		var record domain.ReminderRecord
+		err := rows.Scan(
+			&record.PullRequestID,
+			&record.ReviewerID,
+			&record.TeamName,
+			&record.ReminderCount,
+			&record.LastRemindedAt,
+			&record.LastError,
+		)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (r *ReminderRepo) ListTeamThresholds(ctx context.Context) ([]domain.TeamReminderThreshold, error) {
+	rows, err := r.pool.Query(ctx, `SELECT name, reminder_after_minutes FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	thresholds := []domain.TeamReminderThreshold{}
+	for rows.Next() {
+		var threshold domain.TeamReminderThreshold
+		if err := rows.Scan(&threshold.TeamName, &threshold.ThresholdMinutes); err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return thresholds, nil
+}
+
+// SetTeamThreshold задаёт порог команды, nil возвращает порог из конфигурации
+func (r *ReminderRepo) SetTeamThreshold(ctx context.Context, teamName string, minutes *int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE teams SET reminder_after_minutes = $2 WHERE name = $1`,
+		teamName,
+		minutes,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ScheduleRepository interface {
+	GetTeamSchedule(ctx context.Context, teamName string) (*domain.WorkSchedule, error)
+	SetTeamSchedule(ctx context.Context, teamName string, schedule *domain.WorkSchedule) error
+	GetUserSchedule(ctx context.Context, userID string) (*domain.WorkSchedule, error)
+	SetUserSchedule(ctx context.Context, userID string, schedule *domain.WorkSchedule) error
+	ListHolidays(ctx context.Context, teamName string) ([]domain.Holiday, error)
+	SetHolidays(ctx context.Context, teamName string, holidays []domain.Holiday) error
+}
+
+type ScheduleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewScheduleRepo(pool *pgxpool.Pool) ScheduleRepository {
+	return &ScheduleRepo{pool: pool}
+}
+
+// GetTeamSchedule возвращает nil, если у команды нет расписания
+func (r *ScheduleRepo) GetTeamSchedule(ctx context.Context, teamName string) (*domain.WorkSchedule, error) {
+	return scanSchedule(r.pool.QueryRow(ctx,
+		`SELECT COALESCE(timezone, ''), COALESCE(work_start, ''), COALESCE(work_end, ''), work_days
+		 FROM teams WHERE name = $1`,
+		teamName,
+	))
+}
+
+// SetTeamSchedule задаёт расписание команды, nil удаляет его
+func (r *ScheduleRepo) SetTeamSchedule(ctx context.Context, teamName string, schedule *domain.WorkSchedule) error {
+	if schedule == nil {
+		schedule = &domain.WorkSchedule{}
+	}
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE teams
+		 SET timezone = NULLIF($2, ''), work_start = NULLIF($3, ''), work_end = NULLIF($4, ''), work_days = $5
+		 WHERE name = $1`,
+		teamName,
+		schedule.Timezone,
+		schedule.WorkStart,
+		schedule.WorkEnd,
+		workDays(schedule.WorkDays),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// GetUserSchedule возвращает nil, если у пользователя нет своего расписания
+func (r *ScheduleRepo) GetUserSchedule(ctx context.Context, userID string) (*domain.WorkSchedule, error) {
+	return scanSchedule(r.pool.QueryRow(ctx,
+		`SELECT COALESCE(timezone, ''), COALESCE(work_start, ''), COALESCE(work_end, ''), work_days
+		 FROM users WHERE id = $1`,
+		userID,
+	))
+}
+
+// SetUserSchedule задаёт расписание пользователя, nil удаляет его
+func (r *ScheduleRepo) SetUserSchedule(ctx context.Context, userID string, schedule *domain.WorkSchedule) error {
+	if schedule == nil {
+		schedule = &domain.WorkSchedule{}
+	}
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users
+		 SET timezone = NULLIF($2, ''), work_start = NULLIF($3, ''), work_end = NULLIF($4, ''), work_days = $5
+		 WHERE id = $1`,
+		userID,
+		schedule.Timezone,
+		schedule.WorkStart,
+		schedule.WorkEnd,
+		workDays(schedule.WorkDays),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ScheduleRepo) ListHolidays(ctx context.Context, teamName string) ([]domain.Holiday, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT h.day, COALESCE(h.name, '')
+		 FROM team_holidays h
+		 JOIN teams t ON t.id = h.team_id
+		 WHERE t.name = $1
+		 ORDER BY h.day`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []domain.Holiday{}
+	for rows.Next() {
+		var day time.Time
+		var holiday domain.Holiday
+		if err := rows.Scan(&day, &holiday.Name); err != nil {
+			return nil, err
+		}
+		holiday.Date = day.Format(time.DateOnly)
+		holidays = append(holidays, holiday)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+// SetHolidays заменяет календарь праздников команды
+func (r *ScheduleRepo) SetHolidays(ctx context.Context, teamName string, holidays []domain.Holiday) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var teamID string
+	err = tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1 FOR UPDATE`, teamName).Scan(&teamID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM team_holidays WHERE team_id = $1`, teamID); err != nil {
+		return err
+	}
+
+	for _, holiday := range holidays {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO team_holidays (team_id, day, name) VALUES ($1, $2::date, NULLIF($3, ''))
+			 ON CONFLICT (team_id, day) DO UPDATE SET name = EXCLUDED.name`,
+			teamID,
+			holiday.Date,
+			holiday.Name,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanSchedule(row pgx.Row) (*domain.WorkSchedule, error) {
+	var schedule domain.WorkSchedule
+	err := row.Scan(&schedule.Timezone, &schedule.WorkStart, &schedule.WorkEnd, &schedule.WorkDays)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if schedule.Timezone == "" && schedule.WorkStart == "" && schedule.WorkEnd == "" && len(schedule.WorkDays) == 0 {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+// workDays - пустой список хранится как NULL
+func workDays(days []int) []int {
+	if len(days) == 0 {
+		return nil
+	}
+	return days
+}
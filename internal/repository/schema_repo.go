@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SchemaRepository interface {
+	// Version возвращает версию схемы из schema_migrations, 0 - миграции не применялись
+	Version(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+type SchemaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSchemaRepo(pool *pgxpool.Pool) SchemaRepository {
+	return &SchemaRepo{pool: pool}
+}
+
+func (r *SchemaRepo) Version(ctx context.Context) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := r.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// Таблицы ещё нет или она пуста: миграции не применялись
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "42P01") {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepository interface {
+	// ListMerged возвращает PR, слитые в [from, to); пустой teamName - все команды
+	ListMerged(ctx context.Context, from, to time.Time, teamName string) ([]domain.TurnaroundSample, error)
+	// ListVerdicts возвращает вердикты, вынесенные в [from, to)
+	ListVerdicts(ctx context.Context, from, to time.Time, teamName string) ([]domain.TurnaroundSample, error)
+	// Turnaround считает перцентили по уже посчитанным Seconds. Рабочие расписания
+	// в SQL недоступны, поэтому длительности приходят из сервиса, а ранжируют их
+	// оконные функции.
+	Turnaround(ctx context.Context, merged, verdicts []domain.TurnaroundSample) (*domain.TurnaroundStats, error)
+}
+
+type StatsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepo(pool *pgxpool.Pool) StatsRepository {
+	return &StatsRepo{pool: pool}
+}
+
+func (r *StatsRepo) ListMerged(ctx context.Context, from, to time.Time, teamName string) ([]domain.TurnaroundSample, error) {
+	return r.listSamples(ctx,
+		`SELECT COALESCE(t.name, ''), p.author_id::text, p.created_at, p.merged_at
+		 FROM prs p
+		 LEFT JOIN teams t ON t.id = p.team_id
+		 WHERE p.status = 'MERGED' AND p.created_at IS NOT NULL
+		   AND p.merged_at >= $1 AND p.merged_at < $2
+		   AND ($3 = '' OR t.name = $3)`,
+		from, to, teamName,
+	)
+}
+
+func (r *StatsRepo) ListVerdicts(ctx context.Context, from, to time.Time, teamName string) ([]domain.TurnaroundSample, error) {
+	return r.listSamples(ctx,
+		`SELECT COALESCE(t.name, ''), rv.user_id::text, rv.assigned_at, rv.verdict_at
+		 FROM pr_reviewers rv
+		 JOIN prs p ON p.id = rv.pr_id
+		 LEFT JOIN teams t ON t.id = p.team_id
+		 WHERE rv.verdict_at >= $1 AND rv.verdict_at < $2
+		   AND ($3 = '' OR t.name = $3)`,
+		from, to, teamName,
+	)
+}
+
+func (r *StatsRepo) listSamples(ctx context.Context, query string, args ...any) ([]domain.TurnaroundSample, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := []domain.TurnaroundSample{}
+	for rows.Next() {
+		var sample domain.TurnaroundSample
+		if err := rows.Scan(&sample.TeamName, &sample.UserID, &sample.Start, &sample.End); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// Перцентиль по методу ближайшего ранга: наименьшее значение, ранг которого
+// в своей группе не меньше ceil(p * n). Ранг и размер группы считают оконные функции.
+const percentileColumns = `count(*),
+	min(seconds) FILTER (WHERE rn >= ceil(0.50 * n)),
+	min(seconds) FILTER (WHERE rn >= ceil(0.90 * n)),
+	min(seconds) FILTER (WHERE rn >= ceil(0.99 * n))`
+
+func (r *StatsRepo) Turnaround(ctx context.Context, merged, verdicts []domain.TurnaroundSample) (*domain.TurnaroundStats, error) {
+	stats := &domain.TurnaroundStats{
+		Teams:     []domain.TeamTurnaround{},
+		Authors:   []domain.AuthorTurnaround{},
+		Reviewers: []domain.ReviewerTurnaround{},
+	}
+
+	mergedTeams, mergedAuthors, mergedSeconds := sampleColumns(merged)
+	_, verdictReviewers, verdictSeconds := sampleColumns(verdicts)
+
+	// Время до слияния по командам и авторам и время до вердикта по ревьюверам одним проходом
+	rows, err := r.pool.Query(ctx,
+		`WITH merged AS (
+		     SELECT * FROM unnest($1::text[], $2::text[], $3::float8[]) AS m(team_name, author_id, seconds)
+		 ), verdicts AS (
+		     SELECT * FROM unnest($4::text[], $5::float8[]) AS v(reviewer_id, seconds)
+		 ), grouped AS (
+		     SELECT 'team' AS dimension, team_name AS key, seconds FROM merged WHERE team_name <> ''
+		     UNION ALL
+		     SELECT 'author', author_id, seconds FROM merged
+		     UNION ALL
+		     SELECT 'reviewer', reviewer_id, seconds FROM verdicts
+		 ), ranked AS (
+		     SELECT dimension, key, seconds,
+		            row_number() OVER (PARTITION BY dimension, key ORDER BY seconds) AS rn,
+		            count(*) OVER (PARTITION BY dimension, key) AS n
+		     FROM grouped
+		 )
+		 SELECT dimension, key, `+percentileColumns+`
+		 FROM ranked
+		 GROUP BY dimension, key
+		 ORDER BY dimension, key`,
+		mergedTeams,
+		mergedAuthors,
+		mergedSeconds,
+		verdictReviewers,
+		verdictSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dimension, key string
+		var p domain.Percentiles
+		if err := rows.Scan(&dimension, &key, &p.Count, &p.P50, &p.P90, &p.P99); err != nil {
+			return nil, err
+		}
+		switch dimension {
+		case "team":
+			stats.Teams = append(stats.Teams, domain.TeamTurnaround{TeamName: key, TimeToMerge: p})
+		case "author":
+			stats.Authors = append(stats.Authors, domain.AuthorTurnaround{AuthorID: key, TimeToMerge: p})
+		default:
+			stats.Reviewers = append(stats.Reviewers, domain.ReviewerTurnaround{ReviewerID: key, TimeToVerdict: p})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// sampleColumns раскладывает выборку по массивам для unnest
+func sampleColumns(samples []domain.TurnaroundSample) (teams, users []string, seconds []float64) {
+	teams = make([]string, len(samples))
+	users = make([]string, len(samples))
+	seconds = make([]float64, len(samples))
+	for i, sample := range samples {
+		teams[i] = sample.TeamName
+		users[i] = sample.UserID
+		seconds[i] = sample.Seconds
+	}
+	return teams, users, seconds
+}
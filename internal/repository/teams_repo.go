@@ -10,6 +10,7 @@ type TeamRepository interface {
 	Create(ctx context.Context, team *domain.Team) error
 	GetByID(ctx context.Context, teamID string) (*domain.Team, error)
 	GetByName(ctx context.Context, name string) (*domain.Team, error)
+	SetReviewSLA(ctx context.Context, name string, minutes *int) error
 }
 
 type TeamRepo struct {
@@ -29,8 +30,9 @@ func (r *TeamRepo) Create(ctx context.Context, team *domain.Team) error {
 
 	var teamID string
 	err = tx.QueryRow(ctx,
-		`INSERT INTO teams (name) VALUES ($1) RETURNING id`,
+		`INSERT INTO teams (name, review_sla_minutes) VALUES ($1, $2) RETURNING id`,
 		team.TeamName,
+		team.ReviewSLAMinutes,
 	).Scan(&teamID)
 	if err != nil {
 		return err
@@ -80,10 +82,11 @@ func (r *TeamRepo) getOrCreateUser(ctx context.Context, member *domain.TeamMembe
 
 func (r *TeamRepo) GetByID(ctx context.Context, teamID string) (*domain.Team, error) {
 	var teamName string
+	var reviewSLA *int
 	err := r.pool.QueryRow(ctx,
-		`SELECT name FROM teams WHERE id = $1`,
+		`SELECT name, review_sla_minutes FROM teams WHERE id = $1`,
 		teamID,
-	).Scan(&teamName)
+	).Scan(&teamName, &reviewSLA)
 	if err != nil {
 		return nil, err
 	}
@@ -116,17 +119,19 @@ func (r *TeamRepo) GetByID(ctx context.Context, teamID string) (*domain.Team, er
 	}
 
 	return &domain.Team{
-		TeamName: teamName,
-		Members:  members,
+		TeamName:         teamName,
+		Members:          members,
+		ReviewSLAMinutes: reviewSLA,
 	}, nil
 }
 
 func (r *TeamRepo) GetByName(ctx context.Context, name string) (*domain.Team, error) {
 	var teamID string
+	var reviewSLA *int
 	err := r.pool.QueryRow(ctx,
-		`SELECT id FROM teams WHERE name = $1`,
+		`SELECT id, review_sla_minutes FROM teams WHERE name = $1`,
 		name,
-	).Scan(&teamID)
+	).Scan(&teamID, &reviewSLA)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +164,24 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (*domain.Team, er
 	}
 
 	return &domain.Team{
-		TeamName: name,
-		Members:  members,
+		TeamName:         name,
+		Members:          members,
+		ReviewSLAMinutes: reviewSLA,
 	}, nil
 }
+
+// SetReviewSLA задаёт SLA ревью команды, nil выключает эскалацию
+func (r *TeamRepo) SetReviewSLA(ctx context.Context, name string, minutes *int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE teams SET review_sla_minutes = $2 WHERE name = $1`,
+		name,
+		minutes,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
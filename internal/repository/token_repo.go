@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken, tokenHash string) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	List(ctx context.Context) ([]domain.APIToken, error)
+	Revoke(ctx context.Context, tokenID string) error
+}
+
+type TokenRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTokenRepo(pool *pgxpool.Pool) TokenRepository {
+	return &TokenRepo{pool: pool}
+}
+
+func (r *TokenRepo) Create(ctx context.Context, token *domain.APIToken, tokenHash string) error {
+	var userID *string
+	if token.UserID != "" {
+		userID = &token.UserID
+	}
+
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO api_tokens (name, role, user_id, token_hash)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		token.Name,
+		token.Role,
+		userID,
+		tokenHash,
+	).Scan(&token.TokenID, &token.CreatedAt)
+}
+
+// GetByHash ищет действующий (не отозванный) токен
+func (r *TokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	token, err := scanToken(r.pool.QueryRow(ctx,
+		`SELECT id, name, role, user_id, created_at, revoked_at
+		 FROM api_tokens
+		 WHERE token_hash = $1 AND revoked_at IS NULL`,
+		tokenHash,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *TokenRepo) List(ctx context.Context) ([]domain.APIToken, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, name, role, user_id, created_at, revoked_at
+		 FROM api_tokens
+		 ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.APIToken{}
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *TokenRepo) Revoke(ctx context.Context, tokenID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE api_tokens SET revoked_at = now()
+		 WHERE id = $1 AND revoked_at IS NULL`,
+		tokenID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanToken(row pgx.Row) (*domain.APIToken, error) {
+	var token domain.APIToken
+	var userID *string
+	err := row.Scan(&token.TokenID, &token.Name, &token.Role, &userID, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if userID != nil {
+		token.UserID = *userID
+	}
+	return &token, nil
+}
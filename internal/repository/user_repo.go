@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
-	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"errors"
 
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,25 +36,78 @@ func (r *UserRepo) GetByUserID(ctx context.Context, userID string) (*domain.User
 
 	var u domain.User
 	if err := row.Scan(&u.UserID, &u.Username, &u.IsActive, &u.TeamName); err != nil {
-		return nil, domain.ErrNotFound
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidUUID(err) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
 	}
 	return &u, nil
 }
 
 func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
-	tag, err := r.pool.Exec(ctx, `UPDATE users SET is_active = $1 WHERE id = $2`, isActive, userID)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем пользователя, чтобы понять, меняется ли флаг на самом деле
+	var wasActive bool
+	err = tx.QueryRow(ctx, `SELECT is_active FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&wasActive)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidUUID(err) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
-	if tag.RowsAffected() == 0 {
-		return nil, domain.ErrNotFound
+
+	_, err = tx.Exec(ctx, `UPDATE users SET is_active = $1 WHERE id = $2`, isActive, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wasActive != isActive {
+		var teamName string
+		err = tx.QueryRow(ctx, `
+			SELECT COALESCE(t.name, '')
+			FROM users u
+			LEFT JOIN team_members tm ON u.id = tm.user_id
+			LEFT JOIN teams t ON tm.team_id = t.id
+			WHERE u.id = $1
+			LIMIT 1
+		`, userID).Scan(&teamName)
+		if err != nil {
+			return nil, err
+		}
+
+		if isActive {
+			err = insertEvent(ctx, tx, domain.EventUserActivated, userID, domain.UserActivatedPayload{
+				UserID:   userID,
+				TeamName: teamName,
+			})
+		} else {
+			err = insertEvent(ctx, tx, domain.EventUserDeactivated, userID, domain.UserDeactivatedPayload{
+				UserID:   userID,
+				TeamName: teamName,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
 	}
 	return r.GetByUserID(ctx, userID)
 }
 
 func (r *UserRepo) GetPullRequests(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
-	rows, err := r.pool.Query(ctx, `SELECT id, pull_request_name, author_id, status FROM prs WHERE id IN (
-    SELECT pr_id FROM pr_reviewers WHERE user_id = $1 )`, userID)
+	rows, err := r.pool.Query(ctx, `SELECT p.id, p.pull_request_name, p.author_id, p.status, p.created_at, COALESCE(f.url, '')
+		FROM prs p
+		LEFT JOIN forge_pull_requests f ON f.pr_id = p.id
+		WHERE p.id IN (SELECT pr_id FROM pr_reviewers WHERE user_id = $1)
+		ORDER BY p.created_at, p.id`, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +122,8 @@ func (r *UserRepo) GetPullRequests(ctx context.Context, userID string) ([]domain
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.CreatedAt,
+			&pr.URL,
 		)
 		if err != nil {
 			return nil, err
@@ -79,3 +137,9 @@ func (r *UserRepo) GetPullRequests(ctx context.Context, userID string) ([]domain
 
 	return prs, nil
 }
+
+// isInvalidUUID - Postgres не смог разобрать идентификатор
+func isInvalidUUID(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22P02"
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PendingDelivery - доставка, захваченная отправщиком, вместе с адресом и секретом подписки
+type PendingDelivery struct {
+	Delivery domain.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	List(ctx context.Context) ([]domain.WebhookSubscription, error)
+	Delete(ctx context.Context, webhookID string) error
+	Enqueue(ctx context.Context, event domain.Event, body []byte) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]PendingDelivery, error)
+	MarkDelivered(ctx context.Context, deliveryID int64, statusCode int) error
+	MarkFailed(ctx context.Context, deliveryID int64, statusCode int, errMsg string, nextAttemptAt *time.Time) error
+	ListDead(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error)
+}
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) WebhookRepository {
+	return &WebhookRepo{pool: pool}
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO webhook_subscriptions (url, event_types, secret)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, is_active, created_at`,
+		sub.URL,
+		sub.EventTypes,
+		sub.Secret,
+	).Scan(&sub.WebhookID, &sub.IsActive, &sub.CreatedAt)
+}
+
+func (r *WebhookRepo) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, url, event_types, is_active, created_at
+		 FROM webhook_subscriptions
+		 ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []domain.WebhookSubscription{}
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		err := rows.Scan(&sub.WebhookID, &sub.URL, &sub.EventTypes, &sub.IsActive, &sub.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+func (r *WebhookRepo) Delete(ctx context.Context, webhookID string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, webhookID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue создаёт доставки события для всех активных подписок на его тип.
+// Повторная доставка того же события из outbox не создаёт дублей.
+func (r *WebhookRepo) Enqueue(ctx context.Context, event domain.Event, body []byte) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		 SELECT id, $1::bigint, $2::text, $3::jsonb
+		 FROM webhook_subscriptions
+		 WHERE is_active = true AND $2 = ANY(event_types)
+		 ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID,
+		event.Type,
+		body,
+	)
+	return err
+}
+
+// Claim захватывает готовые к отправке доставки, откладывая их на время lease,
+// чтобы другой экземпляр сервиса не отправил их параллельно
+func (r *WebhookRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]PendingDelivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`WITH claimed AS (
+		     UPDATE webhook_deliveries
+		     SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		     WHERE id IN (
+		         SELECT id FROM webhook_deliveries
+		         WHERE status = 'PENDING' AND next_attempt_at <= now()
+		         ORDER BY next_attempt_at, id
+		         LIMIT $1
+		         FOR UPDATE SKIP LOCKED
+		     )
+		     RETURNING *
+		 )
+		 SELECT c.id, c.subscription_id, c.event_id, c.event_type, c.payload, c.status, c.attempts, c.created_at, s.url, s.secret
+		 FROM claimed c
+		 JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		 ORDER BY c.id`,
+		limit,
+		lease.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []PendingDelivery
+	for rows.Next() {
+		var p PendingDelivery
+		err := rows.Scan(
+			&p.Delivery.DeliveryID,
+			&p.Delivery.WebhookID,
+			&p.Delivery.EventID,
+			&p.Delivery.EventType,
+			&p.Delivery.Payload,
+			&p.Delivery.Status,
+			&p.Delivery.Attempts,
+			&p.Delivery.CreatedAt,
+			&p.URL,
+			&p.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, deliveryID int64, statusCode int) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'DELIVERED', attempts = attempts + 1, last_status_code = $2, last_error = NULL, delivered_at = now()
+		 WHERE id = $1`,
+		deliveryID,
+		statusCode,
+	)
+	return err
+}
+
+// MarkFailed фиксирует неудачную попытку. Без nextAttemptAt доставка уходит в dead-letter.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, deliveryID int64, statusCode int, errMsg string, nextAttemptAt *time.Time) error {
+	status := domain.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = domain.WebhookDeliveryDead
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = $2,
+		     attempts = attempts + 1,
+		     last_status_code = NULLIF($3, 0),
+		     last_error = $4,
+		     next_attempt_at = COALESCE($5, next_attempt_at)
+		 WHERE id = $1`,
+		deliveryID,
+		status,
+		statusCode,
+		errMsg,
+		nextAttemptAt,
+	)
+	return err
+}
+
+func (r *WebhookRepo) ListDead(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+deliveryColumns+`
+		 FROM webhook_deliveries
+		 WHERE status = 'DEAD' AND ($1 = '' OR subscription_id::text = $1)
+		 ORDER BY id`,
+		webhookID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver возвращает доставку из dead-letter в очередь с обнулённым счётчиком попыток.
+// Доставленные и ещё ожидающие доставки не трогаются, для них возвращается ErrNotFound.
+func (r *WebhookRepo) Redeliver(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error) {
+	row := r.pool.QueryRow(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'PENDING', attempts = 0, next_attempt_at = now()
+		 WHERE id = $1 AND status = 'DEAD'
+		 RETURNING `+deliveryColumns,
+		deliveryID,
+	)
+
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	COALESCE(last_error, ''), COALESCE(last_status_code, 0), created_at, delivered_at`
+
+func scanDelivery(row pgx.Row) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var payload []byte
+	err := row.Scan(
+		&d.DeliveryID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&d.LastError,
+		&d.LastStatusCode,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = json.RawMessage(payload)
+	return &d, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type AuthService interface {
+	Authenticate(ctx context.Context, token string) (*domain.Identity, error)
+	CreateToken(ctx context.Context, token *domain.APIToken) (*domain.APIToken, error)
+	ListTokens(ctx context.Context) ([]domain.APIToken, error)
+	RevokeToken(ctx context.Context, tokenID string) error
+}
+
+// TokenVerifier проверяет токены внешнего выпускающего, например JWT шлюза
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*domain.Identity, error)
+}
+
+type authService struct {
+	tokenRepo repository.TokenRepository
+	// Хэши админских токенов из конфигурации (ADMIN_TOKENS)
+	adminTokenHashes []string
+	// nil - JWT не принимаются
+	jwtVerifier TokenVerifier
+}
+
+func NewAuthService(tokenRepo repository.TokenRepository, adminTokens []string, jwtVerifier TokenVerifier) AuthService {
+	hashes := make([]string, 0, len(adminTokens))
+	for _, token := range adminTokens {
+		if token != "" {
+			hashes = append(hashes, auth.HashToken(token))
+		}
+	}
+	return &authService{
+		tokenRepo:        tokenRepo,
+		adminTokenHashes: hashes,
+		jwtVerifier:      jwtVerifier,
+	}
+}
+
+func (s *authService) Authenticate(ctx context.Context, token string) (*domain.Identity, error) {
+	if token == "" {
+		return nil, unauthorized("missing bearer token")
+	}
+
+	if s.jwtVerifier != nil && auth.LooksLikeJWT(token) {
+		identity, err := s.jwtVerifier.Verify(ctx, token)
+		if err != nil {
+			return nil, unauthorized("invalid token: " + err.Error())
+		}
+		return identity, nil
+	}
+
+	hash := auth.HashToken(token)
+
+	for _, adminHash := range s.adminTokenHashes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(adminHash)) == 1 {
+			return &domain.Identity{Role: domain.RoleAdmin, Subject: "config"}, nil
+		}
+	}
+
+	stored, err := s.tokenRepo.GetByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, unauthorized("invalid token")
+		}
+		return nil, err
+	}
+
+	return &domain.Identity{
+		Role:    stored.Role,
+		UserID:  stored.UserID,
+		Subject: stored.Name,
+		TokenID: stored.TokenID,
+	}, nil
+}
+
+func (s *authService) CreateToken(ctx context.Context, token *domain.APIToken) (*domain.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.CreateToken")
+	defer span.End()
+
+	if token.Name == "" {
+		return nil, badRequest("name must not be empty")
+	}
+	switch token.Role {
+	case domain.RoleAdmin:
+	case domain.RoleUser:
+		if token.UserID == "" {
+			return nil, badRequest("user_id is required for user tokens")
+		}
+	default:
+		return nil, badRequest("role must be admin or user")
+	}
+
+	plain, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Create(ctx, token, auth.HashToken(plain)); err != nil {
+		var pgErr *pgconn.PgError
+		if isInvalidUUID(err) || (errors.As(err, &pgErr) && pgErr.Code == "23503") {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "user not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	// Токен отдаётся только в ответе на создание
+	token.Token = plain
+	return token, nil
+}
+
+func (s *authService) ListTokens(ctx context.Context) ([]domain.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ListTokens")
+	defer span.End()
+
+	return s.tokenRepo.List(ctx)
+}
+
+func (s *authService) RevokeToken(ctx context.Context, tokenID string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.RevokeToken")
+	defer span.End()
+
+	err := s.tokenRepo.Revoke(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "token not found",
+				},
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func unauthorized(message string) error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeUnauthorized,
+			Message: message,
+		},
+	}
+}
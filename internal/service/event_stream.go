@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/events"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// Размер страницы при догрузке пропущенных событий из outbox
+const replayPageSize = 500
+
+// StreamEventTypes - события, которые отдаются в потоке для дашбордов
+var StreamEventTypes = []domain.EventType{
+	domain.EventPRCreated,
+	domain.EventPRMerged,
+	domain.EventReviewerReassigned,
+	domain.EventUserActivated,
+	domain.EventUserDeactivated,
+}
+
+// EventFilter - отбор событий потока по команде и пользователю
+type EventFilter struct {
+	TeamName string
+	UserID   string
+}
+
+// eventSubjects - поля полезной нагрузки, по которым фильтруется поток
+type eventSubjects struct {
+	TeamName      string   `json:"team_name"`
+	AuthorID      string   `json:"author_id"`
+	Reviewers     []string `json:"assigned_reviewers"`
+	OldReviewerID string   `json:"old_reviewer_id"`
+	NewReviewerID string   `json:"new_reviewer_id"`
+	UserID        string   `json:"user_id"`
+}
+
+// Match - относится ли событие к команде и пользователю фильтра
+func (f EventFilter) Match(event domain.Event) bool {
+	if !slices.Contains(StreamEventTypes, event.Type) {
+		return false
+	}
+	if f.TeamName == "" && f.UserID == "" {
+		return true
+	}
+
+	var s eventSubjects
+	if err := json.Unmarshal(event.Payload, &s); err != nil {
+		return false
+	}
+	if f.TeamName != "" && s.TeamName != f.TeamName {
+		return false
+	}
+	if f.UserID != "" {
+		return s.AuthorID == f.UserID || s.UserID == f.UserID ||
+			s.OldReviewerID == f.UserID || s.NewReviewerID == f.UserID ||
+			slices.Contains(s.Reviewers, f.UserID)
+	}
+	return true
+}
+
+type EventStreamService interface {
+	// Stream отправляет в send события с номером доставки после afterSeq из outbox,
+	// затем новые события по мере доставки, в порядке номеров. Блокируется до отмены
+	// ctx или ошибки send. Возвращает ErrStreamClosed, если клиент не успевает
+	// читать или сервер останавливается.
+	Stream(ctx context.Context, afterSeq int64, filter EventFilter, send func(domain.Event) error) error
+}
+
+// ErrStreamClosed - поток закрыт сервером, клиенту нужно переподключиться
+var ErrStreamClosed = errors.New("event stream closed")
+
+type eventStreamService struct {
+	outboxRepo  repository.OutboxRepository
+	broadcaster *events.Broadcaster
+}
+
+func NewEventStreamService(outboxRepo repository.OutboxRepository, broadcaster *events.Broadcaster) EventStreamService {
+	return &eventStreamService{
+		outboxRepo:  outboxRepo,
+		broadcaster: broadcaster,
+	}
+}
+
+func (s *eventStreamService) Stream(ctx context.Context, afterSeq int64, filter EventFilter, send func(domain.Event) error) error {
+	// Подписываемся до догрузки, чтобы не потерять события между ними
+	live, unsubscribe := s.broadcaster.Subscribe()
+	defer unsubscribe()
+
+	lastSeq := afterSeq
+	sendEvent := func(event domain.Event) error {
+		lastSeq = event.Sequence
+		if !filter.Match(event) {
+			return nil
+		}
+		return send(event)
+	}
+
+	// replay догружает из outbox события после lastSeq
+	replay := func() error {
+		for {
+			page, err := s.outboxRepo.ListDispatched(ctx, lastSeq, replayPageSize)
+			if err != nil {
+				return err
+			}
+			for _, event := range page {
+				if err := sendEvent(event); err != nil {
+					return err
+				}
+			}
+			if len(page) < replayPageSize {
+				return nil
+			}
+		}
+	}
+
+	if afterSeq > 0 {
+		if err := replay(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-live:
+			if !ok {
+				return ErrStreamClosed
+			}
+			if event.Sequence <= lastSeq {
+				continue
+			}
+			// Пропуск в номерах: событие доставил другой экземпляр сервиса
+			// или номер пропал при откате. Пропущенное догружается из outbox,
+			// текущее событие уже зафиксировано и придёт в той же догрузке.
+			if lastSeq > 0 && event.Sequence > lastSeq+1 {
+				if err := replay(); err != nil {
+					return err
+				}
+				if event.Sequence <= lastSeq {
+					continue
+				}
+			}
+			if err := sendEvent(event); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/events"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// fakeOutbox - доставленные события в порядке номеров доставки
+type fakeOutbox struct {
+	repository.OutboxRepository
+
+	mu         sync.Mutex
+	dispatched []domain.Event
+}
+
+func (r *fakeOutbox) add(events ...domain.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dispatched = append(r.dispatched, events...)
+}
+
+func (r *fakeOutbox) ListDispatched(_ context.Context, afterSeq int64, limit int) ([]domain.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var page []domain.Event
+	for _, event := range r.dispatched {
+		if event.Sequence > afterSeq && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func dispatchedEvent(id, seq int64, teamName string) domain.Event {
+	payload, _ := json.Marshal(map[string]string{"team_name": teamName})
+	return domain.Event{ID: id, Sequence: seq, Type: domain.EventPRCreated, Payload: payload}
+}
+
+// collectStream запускает Stream и возвращает функцию, ждущую n событий
+func collectStream(t *testing.T, svc EventStreamService, afterSeq int64, filter EventFilter) func(n int) []int64 {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	received := make(chan domain.Event, 16)
+	go svc.Stream(ctx, afterSeq, filter, func(event domain.Event) error {
+		received <- event
+		return nil
+	})
+
+	return func(n int) []int64 {
+		t.Helper()
+		var seqs []int64
+		for range n {
+			select {
+			case event := <-received:
+				seqs = append(seqs, event.Sequence)
+			case <-time.After(time.Second):
+				t.Fatalf("got %v, want %d events", seqs, n)
+			}
+		}
+		select {
+		case event := <-received:
+			t.Fatalf("unexpected event %d after %v", event.Sequence, seqs)
+		case <-time.After(50 * time.Millisecond):
+		}
+		return seqs
+	}
+}
+
+// waitSubscribed даёт Stream подписаться до рассылки
+func waitSubscribed() {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestStreamResumesBySequence(t *testing.T) {
+	outbox := &fakeOutbox{}
+	// id 5 доставлено позже id 7 (повтор), но раньше по номеру доставки 3
+	outbox.add(dispatchedEvent(1, 1, "a"), dispatchedEvent(7, 2, "a"), dispatchedEvent(5, 3, "a"))
+	broadcaster := events.NewBroadcaster(16)
+	svc := NewEventStreamService(outbox, broadcaster)
+
+	next := collectStream(t, svc, 1, EventFilter{})
+	if got := next(2); !slices.Equal(got, []int64{2, 3}) {
+		t.Fatalf("replayed %v, want [2 3]", got)
+	}
+
+	// Живое событие, уже отданное догрузкой, не повторяется
+	waitSubscribed()
+	broadcaster.Broadcast(dispatchedEvent(5, 3, "a"))
+	event := dispatchedEvent(8, 4, "a")
+	outbox.add(event)
+	broadcaster.Broadcast(event)
+	if got := next(1); !slices.Equal(got, []int64{4}) {
+		t.Errorf("live %v, want [4]", got)
+	}
+}
+
+func TestStreamFillsSequenceGaps(t *testing.T) {
+	outbox := &fakeOutbox{}
+	broadcaster := events.NewBroadcaster(16)
+	svc := NewEventStreamService(outbox, broadcaster)
+
+	next := collectStream(t, svc, 0, EventFilter{TeamName: "a"})
+	waitSubscribed()
+
+	first := dispatchedEvent(1, 1, "a")
+	outbox.add(first)
+	broadcaster.Broadcast(first)
+	if got := next(1); !slices.Equal(got, []int64{1}) {
+		t.Fatalf("live %v, want [1]", got)
+	}
+
+	// 2 и 3 доставил другой экземпляр, 4 не проходит фильтр: догружаются из outbox
+	outbox.add(dispatchedEvent(2, 2, "a"), dispatchedEvent(3, 3, "a"), dispatchedEvent(4, 4, "b"), dispatchedEvent(5, 5, "a"))
+	broadcaster.Broadcast(dispatchedEvent(5, 5, "a"))
+	if got := next(3); !slices.Equal(got, []int64{2, 3, 5}) {
+		t.Errorf("after gap %v, want [2 3 5]", got)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+)
+
+// ExportQuery - фильтры выгрузки из запроса; даты YYYY-MM-DD по UTC включительно
+type ExportQuery struct {
+	TeamName string
+	From     string
+	To       string
+	Status   string
+}
+
+type ExportService interface {
+	// Ошибка фильтров возвращается до первого вызова fn
+	ExportPullRequests(ctx context.Context, query ExportQuery, fn func(domain.PullRequestExport) error) error
+	ExportAssignments(ctx context.Context, query ExportQuery, fn func(domain.AssignmentExport) error) error
+}
+
+type exportService struct {
+	exportRepo repository.ExportRepository
+}
+
+func NewExportService(exportRepo repository.ExportRepository) ExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+	}
+}
+
+func (s *exportService) ExportPullRequests(ctx context.Context, query ExportQuery, fn func(domain.PullRequestExport) error) error {
+	ctx, span := tracing.Start(ctx, "ExportService.ExportPullRequests")
+	defer span.End()
+
+	filter, err := parseExportQuery(query)
+	if err != nil {
+		return err
+	}
+	return s.exportRepo.StreamPullRequests(ctx, filter, fn)
+}
+
+func (s *exportService) ExportAssignments(ctx context.Context, query ExportQuery, fn func(domain.AssignmentExport) error) error {
+	ctx, span := tracing.Start(ctx, "ExportService.ExportAssignments")
+	defer span.End()
+
+	filter, err := parseExportQuery(query)
+	if err != nil {
+		return err
+	}
+	return s.exportRepo.StreamAssignments(ctx, filter, fn)
+}
+
+func parseExportQuery(query ExportQuery) (domain.ExportFilter, error) {
+	filter := domain.ExportFilter{
+		TeamName: query.TeamName,
+		Status:   domain.PRStatus(query.Status),
+	}
+
+	switch filter.Status {
+	case "", domain.PRStatusOpen, domain.PRStatusMerged:
+	default:
+		return filter, badRequest("status must be OPEN or MERGED")
+	}
+
+	if query.From != "" {
+		from, err := parseDate("from", query.From)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := parseDate("to", query.To)
+		if err != nil {
+			return filter, err
+		}
+		// Дата to включительно
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, badRequest("from must not be after to")
+	}
+
+	return filter, nil
+}
+
+// parseDate разбирает дату YYYY-MM-DD из параметра field как полночь по UTC
+func parseDate(field, value string) (time.Time, error) {
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, badRequest(field + " must be a date YYYY-MM-DD")
+	}
+	return day, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/auth"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ForgeService interface {
+	// HandlePREvent применяет событие PR с GitHub/GitLab. Для событий, которые
+	// не требуют действий, возвращает nil без ошибки.
+	HandlePREvent(ctx context.Context, event *domain.ForgePREvent) (*domain.ForgePullRequest, error)
+	MapUser(ctx context.Context, user *domain.ForgeUser) (*domain.ForgeUser, error)
+	ListUsers(ctx context.Context, forge domain.Forge) ([]domain.ForgeUser, error)
+	ListSyncFailures(ctx context.Context) ([]domain.ForgeSyncFailure, error)
+}
+
+type forgeService struct {
+	forgeRepo repository.ForgeRepository
+	prService PrService
+}
+
+func NewForgeService(forgeRepo repository.ForgeRepository, prService PrService) ForgeService {
+	return &forgeService{
+		forgeRepo: forgeRepo,
+		prService: prService,
+	}
+}
+
+func (s *forgeService) HandlePREvent(ctx context.Context, event *domain.ForgePREvent) (*domain.ForgePullRequest, error) {
+	ctx, span := tracing.Start(ctx, "ForgeService.HandlePREvent")
+	defer span.End()
+
+	// Действия по вебхуку подписываются именем платформы
+	ctx = auth.WithIdentity(ctx, &domain.Identity{Role: domain.RoleAdmin, Subject: string(event.Forge)})
+
+	link, err := s.forgeRepo.GetPullRequest(ctx, event.Forge, event.Repository, event.Number)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	switch event.Action {
+	case domain.ForgeActionOpened:
+		// Повторная доставка или переоткрытие уже известного PR
+		if link != nil {
+			return link, nil
+		}
+		return s.createPR(ctx, event)
+	case domain.ForgeActionMerged:
+		if link == nil {
+			return nil, nil
+		}
+		if _, err := s.prService.MergePR(ctx, link.PullRequestID); err != nil {
+			return nil, err
+		}
+		return link, nil
+	default:
+		// Закрытие без мерджа в сервисе не отражается
+		return nil, nil
+	}
+}
+
+func (s *forgeService) createPR(ctx context.Context, event *domain.ForgePREvent) (*domain.ForgePullRequest, error) {
+	authorID, err := s.forgeRepo.GetUserID(ctx, event.Forge, event.AuthorUsername)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "forge user " + event.AuthorUsername + " is not mapped",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	link := &domain.ForgePullRequest{
+		Forge:      event.Forge,
+		Repository: event.Repository,
+		Number:     event.Number,
+		URL:        event.URL,
+	}
+	// PR и связь создаются в одной транзакции: повторная или параллельная
+	// доставка того же события не создаёт второй PR
+	pr, err := s.prService.CreateLinkedPR(ctx, &domain.PullRequestShort{
+		PullRequestName: event.Title,
+		AuthorID:        authorID,
+		Status:          domain.PRStatusOpen,
+	}, link)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return s.forgeRepo.GetPullRequest(ctx, event.Forge, event.Repository, event.Number)
+	}
+
+	return link, nil
+}
+
+func (s *forgeService) MapUser(ctx context.Context, user *domain.ForgeUser) (*domain.ForgeUser, error) {
+	ctx, span := tracing.Start(ctx, "ForgeService.MapUser")
+	defer span.End()
+
+	if user.Forge != domain.ForgeGitHub && user.Forge != domain.ForgeGitLab {
+		return nil, badRequest("forge must be github or gitlab")
+	}
+	if user.Username == "" {
+		return nil, badRequest("forge_username must not be empty")
+	}
+
+	err := s.forgeRepo.SaveUser(ctx, user)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "user not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *forgeService) ListUsers(ctx context.Context, forge domain.Forge) ([]domain.ForgeUser, error) {
+	ctx, span := tracing.Start(ctx, "ForgeService.ListUsers")
+	defer span.End()
+
+	return s.forgeRepo.ListUsers(ctx, forge)
+}
+
+func (s *forgeService) ListSyncFailures(ctx context.Context) ([]domain.ForgeSyncFailure, error) {
+	ctx, span := tracing.Start(ctx, "ForgeService.ListSyncFailures")
+	defer span.End()
+
+	return s.forgeRepo.ListSyncFailures(ctx)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+)
+
+type HealthService interface {
+	// Ready проверяет базу и версию схемы. Схема новее бинарника допустима:
+	// так бывает при выкатке, пока старые экземпляры ещё работают.
+	Ready(ctx context.Context) domain.Readiness
+}
+
+type healthService struct {
+	schemaRepo     repository.SchemaRepository
+	expectedSchema uint
+}
+
+func NewHealthService(schemaRepo repository.SchemaRepository, expectedSchema uint) HealthService {
+	return &healthService{
+		schemaRepo:     schemaRepo,
+		expectedSchema: expectedSchema,
+	}
+}
+
+func (s *healthService) Ready(ctx context.Context) domain.Readiness {
+	ctx, span := tracing.Start(ctx, "HealthService.Ready")
+	defer span.End()
+
+	readiness := domain.Readiness{Schema: domain.SchemaStatus{Expected: s.expectedSchema}}
+
+	version, dirty, err := s.schemaRepo.Version(ctx)
+	if err != nil {
+		readiness.Reason = fmt.Sprintf("database unavailable: %v", err)
+		return readiness
+	}
+	readiness.Schema.Version = version
+	readiness.Schema.Dirty = dirty
+
+	switch {
+	case dirty:
+		readiness.Reason = fmt.Sprintf("migration %d failed halfway, fix it and run migrate force", version)
+	case version < s.expectedSchema:
+		readiness.Reason = fmt.Sprintf("schema version %d is behind %d, run migrate up", version, s.expectedSchema)
+	default:
+		readiness.Ready = true
+	}
+	return readiness
+}
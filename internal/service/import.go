@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+)
+
+// Форматы файла импорта
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatYAML = "yaml"
+)
+
+type ImportService interface {
+	// ImportTeams разбирает файл импорта и применяет его или, при dryRun, только
+	// возвращает разницу
+	ImportTeams(ctx context.Context, format string, data []byte, dryRun bool) (*domain.ImportResult, error)
+}
+
+type importService struct {
+	importRepo repository.ImportRepository
+}
+
+func NewImportService(importRepo repository.ImportRepository) ImportService {
+	return &importService{
+		importRepo: importRepo,
+	}
+}
+
+func (s *importService) ImportTeams(ctx context.Context, format string, data []byte, dryRun bool) (*domain.ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "ImportService.ImportTeams")
+	defer span.End()
+
+	var rows []domain.ImportRow
+	var err error
+	switch format {
+	case ImportFormatCSV:
+		rows, err = parseImportCSV(data)
+	case ImportFormatYAML:
+		rows, err = parseImportYAML(data)
+	default:
+		return nil, badRequest("format must be csv or yaml")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateImportRows(rows); err != nil {
+		return nil, err
+	}
+
+	logger.AddAttrs(ctx, "import_rows", len(rows), "dry_run", dryRun)
+
+	return s.importRepo.ImportTeams(ctx, rows, dryRun)
+}
+
+// parseImportCSV разбирает CSV с заголовком team,user_id,username[,is_active]
+// в любом порядке колонок; пустой is_active - true
+func parseImportCSV(data []byte) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, badRequest("import file is empty")
+		}
+		return nil, badRequest("invalid csv: " + err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"team", "user_id", "username"} {
+		if _, ok := columns[required]; !ok {
+			return nil, badRequest("csv header must contain team, user_id, username and optionally is_active")
+		}
+	}
+	activeColumn, hasActive := columns["is_active"]
+
+	var rows []domain.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, badRequest("invalid csv: " + err.Error())
+		}
+		row := domain.ImportRow{
+			TeamName: strings.TrimSpace(record[columns["team"]]),
+			UserID:   strings.TrimSpace(record[columns["user_id"]]),
+			Username: strings.TrimSpace(record[columns["username"]]),
+			IsActive: true,
+		}
+		if hasActive {
+			if value := strings.TrimSpace(record[activeColumn]); value != "" {
+				active, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, badRequest(fmt.Sprintf("row %d: is_active must be true or false", len(rows)+1))
+				}
+				row.IsActive = active
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseImportYAML разбирает список строк с полями team, user_id, username, is_active;
+// отсутствующий is_active - true
+func parseImportYAML(data []byte) ([]domain.ImportRow, error) {
+	var items []struct {
+		domain.ImportRow `yaml:",inline"`
+		IsActive         *bool `yaml:"is_active"`
+	}
+	if err := yaml.UnmarshalWithOptions(data, &items, yaml.DisallowUnknownField()); err != nil {
+		return nil, badRequest("invalid yaml: " + yaml.FormatError(err, false, false))
+	}
+
+	rows := make([]domain.ImportRow, 0, len(items))
+	for _, item := range items {
+		row := item.ImportRow
+		row.TeamName = strings.TrimSpace(row.TeamName)
+		row.UserID = strings.TrimSpace(row.UserID)
+		row.Username = strings.TrimSpace(row.Username)
+		row.IsActive = item.IsActive == nil || *item.IsActive
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func validateImportRows(rows []domain.ImportRow) error {
+	if len(rows) == 0 {
+		return badRequest("import file has no rows")
+	}
+
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		// Номер строки данных, без заголовка
+		n := i + 1
+		if row.TeamName == "" || row.UserID == "" || row.Username == "" {
+			return badRequest(fmt.Sprintf("row %d: team, user_id and username are required", n))
+		}
+		id, err := uuid.Parse(row.UserID)
+		if err != nil {
+			return badRequest(fmt.Sprintf("row %d: user_id must be a UUID", n))
+		}
+		// Один и тот же UUID может быть записан по-разному
+		rows[i].UserID = id.String()
+		if prev, ok := seen[rows[i].UserID]; ok {
+			return badRequest(fmt.Sprintf("row %d: user %s is already listed in row %d", n, row.UserID, prev))
+		}
+		seen[rows[i].UserID] = n
+	}
+	return nil
+}
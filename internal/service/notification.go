@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/notify"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultDeliveriesLimit = 100
+	maxDeliveriesLimit     = 1000
+)
+
+type NotificationService interface {
+	SetPreference(ctx context.Context, pref *domain.NotificationPreference) (*domain.NotificationPreference, error)
+	ListPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error)
+	DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error
+	ListDeliveries(ctx context.Context, userID string, status domain.NotificationStatus, limit int) ([]domain.NotificationDelivery, error)
+	GetDigest(ctx context.Context, userID string) (*domain.DigestSubscription, error)
+	SetDigest(ctx context.Context, sub *domain.DigestSubscription) (*domain.DigestSubscription, error)
+	DeleteDigest(ctx context.Context, userID string) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	digestRepo       repository.DigestRepository
+	// Хосты, на которые можно указать вебхук SLACK и MATTERMOST
+	chatHosts notify.ChatHosts
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, digestRepo repository.DigestRepository, chatHosts notify.ChatHosts) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		digestRepo:       digestRepo,
+		chatHosts:        chatHosts,
+	}
+}
+
+var notificationKinds = map[domain.NotificationKind]struct{}{
+	domain.NotificationReviewerAssigned:   {},
+	domain.NotificationReviewerReassigned: {},
+	domain.NotificationReviewReminder:     {},
+	domain.NotificationDailyDigest:        {},
+}
+
+func (s *notificationService) SetPreference(ctx context.Context, pref *domain.NotificationPreference) (*domain.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.SetPreference")
+	defer span.End()
+
+	if pref.UserID == "" {
+		return nil, badRequest("user_id is required")
+	}
+	if err := s.validateAddress(pref.Channel, pref.Address); err != nil {
+		return nil, err
+	}
+	for _, kind := range pref.Kinds {
+		if _, ok := notificationKinds[kind]; !ok {
+			return nil, badRequest("unknown notification kind " + string(kind))
+		}
+	}
+
+	if err := s.notificationRepo.SetPreference(ctx, pref); err != nil {
+		var pgErr *pgconn.PgError
+		if isInvalidUUID(err) || (errors.As(err, &pgErr) && pgErr.Code == "23503") {
+			return nil, userNotFound()
+		}
+		return nil, err
+	}
+	return pref, nil
+}
+
+func (s *notificationService) ListPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.ListPreferences")
+	defer span.End()
+
+	prefs, err := s.notificationRepo.ListPreferences(ctx, userID)
+	if err != nil {
+		if isInvalidUUID(err) {
+			return nil, userNotFound()
+		}
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.DeletePreference")
+	defer span.End()
+
+	err := s.notificationRepo.DeletePreference(ctx, userID, channel)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "notification channel not found",
+				},
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *notificationService) ListDeliveries(ctx context.Context, userID string, status domain.NotificationStatus, limit int) ([]domain.NotificationDelivery, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.ListDeliveries")
+	defer span.End()
+
+	switch status {
+	case "", domain.NotificationPending, domain.NotificationSent, domain.NotificationFailed:
+	default:
+		return nil, badRequest("status must be PENDING, SENT or FAILED")
+	}
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	return s.notificationRepo.ListDeliveries(ctx, userID, status, min(limit, maxDeliveriesLimit))
+}
+
+func (s *notificationService) GetDigest(ctx context.Context, userID string) (*domain.DigestSubscription, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetDigest")
+	defer span.End()
+
+	sub, err := s.digestRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return nil, digestNotFound()
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+// SetDigest подписывает пользователя на ежедневную сводку. По умолчанию сводка
+// приходит в 09:00 UTC с понедельника по пятницу.
+func (s *notificationService) SetDigest(ctx context.Context, sub *domain.DigestSubscription) (*domain.DigestSubscription, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.SetDigest")
+	defer span.End()
+
+	if sub.UserID == "" {
+		return nil, badRequest("user_id is required")
+	}
+	if sub.SendAt == "" {
+		sub.SendAt = "09:00"
+	}
+	if minutes, err := businesstime.ParseClock(sub.SendAt); err != nil || minutes >= 24*60 {
+		return nil, badRequest("send_at must be HH:MM")
+	}
+	if sub.Timezone == "" {
+		sub.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(sub.Timezone); err != nil {
+		return nil, badRequest("unknown timezone " + sub.Timezone)
+	}
+	if len(sub.Days) == 0 {
+		sub.Days = businesstime.DefaultWorkDays
+	}
+	for _, day := range sub.Days {
+		if day < 1 || day > 7 {
+			return nil, badRequest("days must be 1 (Monday) to 7 (Sunday)")
+		}
+	}
+
+	if err := s.digestRepo.Set(ctx, sub); err != nil {
+		var pgErr *pgconn.PgError
+		if isInvalidUUID(err) || (errors.As(err, &pgErr) && pgErr.Code == "23503") {
+			return nil, userNotFound()
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *notificationService) DeleteDigest(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.DeleteDigest")
+	defer span.End()
+
+	err := s.digestRepo.Delete(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return digestNotFound()
+		}
+		return err
+	}
+	return nil
+}
+
+// validateAddress - почта для EMAIL, http(s) URL входящего вебхука на разрешённом хосте для чатов
+func (s *notificationService) validateAddress(channel domain.NotificationChannel, address string) error {
+	switch channel {
+	case domain.NotificationEmail:
+		addr, err := mail.ParseAddress(address)
+		if err != nil || addr.Address != address {
+			return badRequest("address must be an email address")
+		}
+	case domain.NotificationSlack, domain.NotificationMattermost:
+		if !s.chatHosts.Allows(address) {
+			return badRequest("address must be an http(s) webhook URL on an allowed host")
+		}
+	default:
+		return badRequest("channel must be EMAIL, SLACK or MATTERMOST")
+	}
+	return nil
+}
+
+func userNotFound() error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeNotFound,
+			Message: "user not found",
+		},
+	}
+}
+
+func digestNotFound() error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeNotFound,
+			Message: "digest subscription not found",
+		},
+	}
+}
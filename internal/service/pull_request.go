@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/Unitazavr/AvitoPR/internal/auth"
 	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/metrics"
 	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"strings"
@@ -12,8 +16,13 @@ import (
 
 type PrService interface {
 	CreatePR(ctx context.Context, pr *domain.PullRequestShort) (*domain.PullRequest, error)
+	// CreateLinkedPR создаёт PR вместе со связью с PR на GitHub/GitLab.
+	// Возвращает nil без ошибки, если этот PR платформы уже связан.
+	CreateLinkedPR(ctx context.Context, pr *domain.PullRequestShort, link *domain.ForgePullRequest) (*domain.PullRequest, error)
 	MergePR(ctx context.Context, prID string) (*domain.PullRequest, error)
-	ReassignPR(ctx context.Context, pullRequestID, oldUserID string) (pr *domain.PullRequest, newReviewerID string, err error)
+	ReassignPR(ctx context.Context, pullRequestID, oldUserID, reason string) (pr *domain.PullRequest, newReviewerID string, err error)
+	GetHistory(ctx context.Context, prID string) ([]domain.ReviewerHistoryEntry, error)
+	SubmitVerdict(ctx context.Context, prID, userID string, verdict domain.ReviewVerdict) (*domain.PullRequest, error)
 }
 
 type prService struct {
@@ -27,51 +36,90 @@ func NewPrService(prRepo repository.PrRepository) PrService {
 }
 
 func (s *prService) CreatePR(ctx context.Context, pr *domain.PullRequestShort) (*domain.PullRequest, error) {
-	err := s.prRepo.Create(ctx, pr)
+	ctx, span := tracing.Start(ctx, "PrService.CreatePR")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "author_id", pr.AuthorID)
+
+	err := s.prRepo.Create(ctx, pr, auth.IdentityFromContext(ctx).Actor())
 	if err != nil {
+		return nil, createPRError(err)
+	}
 
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, &domain.ErrorResponse{
-				ErrorContent: domain.ErrorBody{
-					Code:    domain.ErrCodePRExists,
-					Message: "PR id already exists",
-				},
-			}
-		}
+	return s.createdPR(ctx, pr.PullRequestID)
+}
 
-		if errors.As(err, &pgErr) && (pgErr.Code == "23502" || pgErr.Code == "23503") {
-			return nil, &domain.ErrorResponse{
-				ErrorContent: domain.ErrorBody{
-					Code:    domain.ErrCodeNotFound,
-					Message: "author or team not found",
-				},
-			}
-		}
+func (s *prService) CreateLinkedPR(ctx context.Context, pr *domain.PullRequestShort, link *domain.ForgePullRequest) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PrService.CreateLinkedPR")
+	defer span.End()
 
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, &domain.ErrorResponse{
-				ErrorContent: domain.ErrorBody{
-					Code:    domain.ErrCodeNotFound,
-					Message: "author or team not found",
-				},
-			}
-		}
+	logger.AddAttrs(ctx, "author_id", pr.AuthorID)
 
-		return nil, err
+	linked, err := s.prRepo.CreateLinked(ctx, pr, link, auth.IdentityFromContext(ctx).Actor())
+	if err != nil {
+		return nil, createPRError(err)
+	}
+	if !linked {
+		return nil, nil
 	}
 
-	// Получаем полный PR с ревьюверами
-	fullPR, err := s.prRepo.GetByID(ctx, pr.PullRequestID)
+	return s.createdPR(ctx, pr.PullRequestID)
+}
+
+// createdPR возвращает только что созданный PR с ревьюверами
+func (s *prService) createdPR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	logger.AddAttrs(ctx, "pr_id", prID)
+
+	fullPR, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).Info("PR created", "reviewers", fullPR.AssignedReviewers)
+
 	return fullPR, nil
 }
 
+// createPRError переводит ошибки базы при создании PR в ответы API
+func createPRError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return &domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodePRExists,
+				Message: "PR id already exists",
+			},
+		}
+	}
+
+	if errors.As(err, &pgErr) && (pgErr.Code == "23502" || pgErr.Code == "23503") {
+		return &domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodeNotFound,
+				Message: "author or team not found",
+			},
+		}
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrorResponse{
+			ErrorContent: domain.ErrorBody{
+				Code:    domain.ErrCodeNotFound,
+				Message: "author or team not found",
+			},
+		}
+	}
+
+	return err
+}
+
 func (s *prService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	err := s.prRepo.Merge(ctx, prID)
+	ctx, span := tracing.Start(ctx, "PrService.MergePR")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "pr_id", prID)
+
+	err := s.prRepo.Merge(ctx, prID, auth.IdentityFromContext(ctx).Actor())
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "already merged") {
 			return nil, &domain.ErrorResponse{
@@ -93,8 +141,13 @@ func (s *prService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 	return fullPR, nil
 }
 
-func (s *prService) ReassignPR(ctx context.Context, pullRequestID, oldUserID string) (*domain.PullRequest, string, error) {
-	newReviewerID, err := s.prRepo.Reassign(ctx, pullRequestID, oldUserID)
+func (s *prService) ReassignPR(ctx context.Context, pullRequestID, oldUserID, reason string) (*domain.PullRequest, string, error) {
+	ctx, span := tracing.Start(ctx, "PrService.ReassignPR")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "pr_id", pullRequestID, "old_reviewer_id", oldUserID)
+
+	newReviewerID, err := s.prRepo.Reassign(ctx, pullRequestID, oldUserID, reason, auth.IdentityFromContext(ctx).Actor())
 	if err != nil {
 
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -127,6 +180,7 @@ func (s *prService) ReassignPR(ctx context.Context, pullRequestID, oldUserID str
 		}
 
 		if strings.Contains(errMsg, "no available reviewers in team") {
+			metrics.IncNoCandidate()
 			return nil, "", &domain.ErrorResponse{
 				ErrorContent: domain.ErrorBody{
 					Code:    domain.ErrCodeNoCandidate,
@@ -147,6 +201,8 @@ func (s *prService) ReassignPR(ctx context.Context, pullRequestID, oldUserID str
 		return nil, "", err
 	}
 
+	logger.FromContext(ctx).Info("reviewer reassigned", "new_reviewer_id", newReviewerID, "reason", reason)
+
 	// Получаем полный PR после переназначения
 	fullPR, err := s.prRepo.GetByID(ctx, pullRequestID)
 	if err != nil {
@@ -155,3 +211,79 @@ func (s *prService) ReassignPR(ctx context.Context, pullRequestID, oldUserID str
 
 	return fullPR, newReviewerID, nil
 }
+
+func (s *prService) GetHistory(ctx context.Context, prID string) ([]domain.ReviewerHistoryEntry, error) {
+	ctx, span := tracing.Start(ctx, "PrService.GetHistory")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "pr_id", prID)
+
+	// Проверяем, что PR существует, чтобы не отдавать пустую историю для несуществующего PR
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidUUID(err) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "PR not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	history, err := s.prRepo.GetHistory(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (s *prService) SubmitVerdict(ctx context.Context, prID, userID string, verdict domain.ReviewVerdict) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PrService.SubmitVerdict")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "pr_id", prID, "reviewer_id", userID)
+
+	if verdict != domain.ReviewVerdictApproved && verdict != domain.ReviewVerdictChangesRequested {
+		return nil, badRequest("verdict must be APPROVED or CHANGES_REQUESTED")
+	}
+
+	err := s.prRepo.SetVerdict(ctx, prID, userID, verdict)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidUUID(err) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "PR not found",
+				},
+			}
+		}
+
+		errMsg := err.Error()
+
+		if strings.Contains(errMsg, "cannot submit verdict for merged PR") {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodePRMerged,
+					Message: "cannot submit verdict on merged PR",
+				},
+			}
+		}
+
+		if strings.Contains(errMsg, "user is not a reviewer of this PR") {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotAssigned,
+					Message: "reviewer is not assigned to this PR",
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("review verdict submitted", "verdict", verdict)
+
+	return s.prRepo.GetByID(ctx, prID)
+}
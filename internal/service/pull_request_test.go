@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakePrRepo отдаёт заданную ошибку на поиск PR
+type fakePrRepo struct {
+	repository.PrRepository
+
+	getErr error
+}
+
+func (r *fakePrRepo) GetByID(_ context.Context, prID string) (*domain.PullRequest, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	return &domain.PullRequest{PullRequestID: prID}, nil
+}
+
+func TestGetHistoryMissingPR(t *testing.T) {
+	dbDown := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		err      error
+		notFound bool
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, notFound: true},
+		{name: "invalid uuid", err: &pgconn.PgError{Code: "22P02"}, notFound: true},
+		{name: "database error", err: dbDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewPrService(&fakePrRepo{getErr: tt.err})
+
+			_, err := svc.GetHistory(context.Background(), "not-a-uuid")
+
+			var errResp *domain.ErrorResponse
+			isNotFound := errors.As(err, &errResp) && errResp.ErrorContent.Code == domain.ErrCodeNotFound
+			if isNotFound != tt.notFound {
+				t.Fatalf("GetHistory error = %v, want not found = %v", err, tt.notFound)
+			}
+			if !tt.notFound && !errors.Is(err, dbDown) {
+				t.Errorf("GetHistory error = %v, want %v", err, dbDown)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+)
+
+// ReminderScheduler - источник состояния планировщика напоминаний
+type ReminderScheduler interface {
+	Status() domain.ReminderSchedulerStatus
+}
+
+type ReminderService interface {
+	Status(ctx context.Context) (*domain.ReminderStatus, error)
+	SetTeamThreshold(ctx context.Context, teamName string, minutes *int) (*domain.TeamReminderThreshold, error)
+}
+
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	scheduler    ReminderScheduler
+}
+
+// NewReminderService - scheduler может быть nil, если напоминания выключены
+func NewReminderService(reminderRepo repository.ReminderRepository, scheduler ReminderScheduler) ReminderService {
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		scheduler:    scheduler,
+	}
+}
+
+func (s *reminderService) Status(ctx context.Context) (*domain.ReminderStatus, error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.Status")
+	defer span.End()
+
+	status := &domain.ReminderStatus{}
+	if s.scheduler != nil {
+		status.Scheduler = s.scheduler.Status()
+	}
+
+	teams, err := s.reminderRepo.ListTeamThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status.Teams = teams
+
+	reminders, err := s.reminderRepo.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status.Reminders = reminders
+
+	return status, nil
+}
+
+func (s *reminderService) SetTeamThreshold(ctx context.Context, teamName string, minutes *int) (*domain.TeamReminderThreshold, error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.SetTeamThreshold")
+	defer span.End()
+
+	if teamName == "" {
+		return nil, badRequest("team_name is required")
+	}
+	if minutes != nil && *minutes <= 0 {
+		return nil, badRequest("threshold_minutes must be positive")
+	}
+
+	if err := s.reminderRepo.SetTeamThreshold(ctx, teamName, minutes); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "team not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	return &domain.TeamReminderThreshold{TeamName: teamName, ThresholdMinutes: minutes}, nil
+}
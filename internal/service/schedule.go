@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+)
+
+type ScheduleService interface {
+	GetTeamCalendar(ctx context.Context, teamName string) (*domain.TeamCalendar, error)
+	SetTeamSchedule(ctx context.Context, teamName string, schedule *domain.WorkSchedule) (*domain.TeamCalendar, error)
+	SetTeamHolidays(ctx context.Context, teamName string, holidays []domain.Holiday) (*domain.TeamCalendar, error)
+	SetUserSchedule(ctx context.Context, userID string, schedule *domain.WorkSchedule) (*domain.WorkSchedule, error)
+}
+
+type scheduleService struct {
+	scheduleRepo repository.ScheduleRepository
+}
+
+func NewScheduleService(scheduleRepo repository.ScheduleRepository) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+func (s *scheduleService) GetTeamCalendar(ctx context.Context, teamName string) (*domain.TeamCalendar, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.GetTeamCalendar")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", teamName)
+
+	schedule, err := s.scheduleRepo.GetTeamSchedule(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, teamNotFound()
+		}
+		return nil, err
+	}
+
+	holidays, err := s.scheduleRepo.ListHolidays(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TeamCalendar{TeamName: teamName, Schedule: schedule, Holidays: holidays}, nil
+}
+
+func (s *scheduleService) SetTeamSchedule(ctx context.Context, teamName string, schedule *domain.WorkSchedule) (*domain.TeamCalendar, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.SetTeamSchedule")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", teamName)
+
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.SetTeamSchedule(ctx, teamName, schedule); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, teamNotFound()
+		}
+		return nil, err
+	}
+
+	return s.GetTeamCalendar(ctx, teamName)
+}
+
+func (s *scheduleService) SetTeamHolidays(ctx context.Context, teamName string, holidays []domain.Holiday) (*domain.TeamCalendar, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.SetTeamHolidays")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", teamName)
+
+	for _, holiday := range holidays {
+		if _, err := time.Parse(time.DateOnly, holiday.Date); err != nil {
+			return nil, badRequest("invalid holiday date " + holiday.Date + ", expected YYYY-MM-DD")
+		}
+	}
+
+	if err := s.scheduleRepo.SetHolidays(ctx, teamName, holidays); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, teamNotFound()
+		}
+		return nil, err
+	}
+
+	return s.GetTeamCalendar(ctx, teamName)
+}
+
+func (s *scheduleService) SetUserSchedule(ctx context.Context, userID string, schedule *domain.WorkSchedule) (*domain.WorkSchedule, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.SetUserSchedule")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "user_id", userID)
+
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.SetUserSchedule(ctx, userID, schedule); err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "user not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	return s.scheduleRepo.GetUserSchedule(ctx, userID)
+}
+
+// validateSchedule проверяет расписание вместе со значениями по умолчанию для пустых полей
+func validateSchedule(schedule *domain.WorkSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+	if _, err := businesstime.New(schedule, nil, nil); err != nil {
+		return badRequest(err.Error())
+	}
+	return nil
+}
+
+func teamNotFound() error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeNotFound,
+			Message: "team not found",
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/businesstime"
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+)
+
+// Диапазон /stats/turnaround по умолчанию, включая сегодняшний день
+const defaultTurnaroundDays = 30
+
+type StatsService interface {
+	// Turnaround принимает даты YYYY-MM-DD по UTC включительно; пустые - последние 30 дней
+	Turnaround(ctx context.Context, from, to, teamName string) (*domain.TurnaroundStats, error)
+}
+
+type statsService struct {
+	statsRepo repository.StatsRepository
+	schedules repository.ScheduleRepository
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[turnaroundKey]cachedTurnaround
+}
+
+type turnaroundKey struct {
+	from, to, teamName string
+}
+
+type cachedTurnaround struct {
+	stats     *domain.TurnaroundStats
+	expiresAt time.Time
+}
+
+// NewStatsService - длительности считаются в рабочем времени по расписаниям
+// из schedules, результаты кэшируются на ttl, 0 выключает кэш
+func NewStatsService(statsRepo repository.StatsRepository, schedules repository.ScheduleRepository, ttl time.Duration) StatsService {
+	return &statsService{
+		statsRepo: statsRepo,
+		schedules: schedules,
+		ttl:       ttl,
+		cache:     map[turnaroundKey]cachedTurnaround{},
+	}
+}
+
+func (s *statsService) Turnaround(ctx context.Context, from, to, teamName string) (*domain.TurnaroundStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.Turnaround")
+	defer span.End()
+
+	toDay := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		day, err := parseDate("to", to)
+		if err != nil {
+			return nil, err
+		}
+		toDay = day
+	}
+	fromDay := toDay.AddDate(0, 0, 1-defaultTurnaroundDays)
+	if from != "" {
+		day, err := parseDate("from", from)
+		if err != nil {
+			return nil, err
+		}
+		fromDay = day
+	}
+	if fromDay.After(toDay) {
+		return nil, badRequest("from must not be after to")
+	}
+
+	key := turnaroundKey{
+		from:     fromDay.Format(time.DateOnly),
+		to:       toDay.Format(time.DateOnly),
+		teamName: teamName,
+	}
+	if stats, ok := s.cached(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.turnaround(ctx, fromDay, toDay.AddDate(0, 0, 1), teamName)
+	if err != nil {
+		return nil, err
+	}
+	stats.From = key.from
+	stats.To = key.to
+	stats.TeamName = teamName
+	stats.GeneratedAt = time.Now().UTC()
+
+	s.store(key, stats)
+	return stats, nil
+}
+
+// turnaround считает длительности в рабочем времени: до слияния - по календарю
+// команды PR, до вердикта - по календарю ревьювера в этой команде
+func (s *statsService) turnaround(ctx context.Context, from, to time.Time, teamName string) (*domain.TurnaroundStats, error) {
+	merged, err := s.statsRepo.ListMerged(ctx, from, to, teamName)
+	if err != nil {
+		return nil, err
+	}
+	verdicts, err := s.statsRepo.ListVerdicts(ctx, from, to, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := businesstime.NewResolver(s.schedules)
+	for i := range merged {
+		calendar, err := calendars.For(ctx, merged[i].TeamName, "")
+		if err != nil {
+			return nil, err
+		}
+		merged[i].Seconds = calendar.Elapsed(merged[i].Start, merged[i].End).Seconds()
+	}
+	for i := range verdicts {
+		calendar, err := calendars.For(ctx, verdicts[i].TeamName, verdicts[i].UserID)
+		if err != nil {
+			return nil, err
+		}
+		verdicts[i].Seconds = calendar.Elapsed(verdicts[i].Start, verdicts[i].End).Seconds()
+	}
+
+	return s.statsRepo.Turnaround(ctx, merged, verdicts)
+}
+
+func (s *statsService) cached(key turnaroundKey) (*domain.TurnaroundStats, bool) {
+	if s.ttl <= 0 {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (s *statsService) store(key turnaroundKey, stats *domain.TurnaroundStats) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Заодно выбрасываем устаревшие записи, чтобы кэш не рос от разных диапазонов
+	now := time.Now()
+	for k, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedTurnaround{stats: stats, expiresAt: now.Add(s.ttl)}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// fakeStatsRepo отдаёт заданные выборки и запоминает, что пришло на ранжирование
+type fakeStatsRepo struct {
+	repository.StatsRepository
+
+	merged, verdicts []domain.TurnaroundSample
+
+	rankedMerged, rankedVerdicts []domain.TurnaroundSample
+}
+
+func (r *fakeStatsRepo) ListMerged(context.Context, time.Time, time.Time, string) ([]domain.TurnaroundSample, error) {
+	return r.merged, nil
+}
+
+func (r *fakeStatsRepo) ListVerdicts(context.Context, time.Time, time.Time, string) ([]domain.TurnaroundSample, error) {
+	return r.verdicts, nil
+}
+
+func (r *fakeStatsRepo) Turnaround(_ context.Context, merged, verdicts []domain.TurnaroundSample) (*domain.TurnaroundStats, error) {
+	r.rankedMerged, r.rankedVerdicts = merged, verdicts
+	return &domain.TurnaroundStats{}, nil
+}
+
+// fakeSchedules - расписания команд и пользователей без праздников
+type fakeSchedules struct {
+	repository.ScheduleRepository
+
+	teams map[string]*domain.WorkSchedule
+	users map[string]*domain.WorkSchedule
+}
+
+func (r *fakeSchedules) GetTeamSchedule(_ context.Context, teamName string) (*domain.WorkSchedule, error) {
+	if schedule, ok := r.teams[teamName]; ok {
+		return schedule, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeSchedules) GetUserSchedule(_ context.Context, userID string) (*domain.WorkSchedule, error) {
+	if schedule, ok := r.users[userID]; ok {
+		return schedule, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeSchedules) ListHolidays(context.Context, string) ([]domain.Holiday, error) {
+	return nil, nil
+}
+
+func TestTurnaroundCountsBusinessTime(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, time.April, day, hour, 0, 0, 0, time.UTC)
+	}
+	// Пятница 17:00 - понедельник 10:00
+	friday, monday := at(24, 17), at(27, 10)
+
+	repo := &fakeStatsRepo{
+		merged: []domain.TurnaroundSample{
+			{TeamName: "backend", UserID: "author", Start: friday, End: monday},
+			{TeamName: "", UserID: "author", Start: friday, End: monday},
+		},
+		verdicts: []domain.TurnaroundSample{
+			{TeamName: "backend", UserID: "reviewer", Start: friday, End: monday},
+			{TeamName: "backend", UserID: "night-owl", Start: friday, End: monday},
+		},
+	}
+	schedules := &fakeSchedules{
+		teams: map[string]*domain.WorkSchedule{
+			"backend": {Timezone: "UTC", WorkStart: "09:00", WorkEnd: "18:00", WorkDays: []int{1, 2, 3, 4, 5}},
+		},
+		users: map[string]*domain.WorkSchedule{
+			"night-owl": {WorkStart: "00:00", WorkEnd: "24:00", WorkDays: []int{1, 2, 3, 4, 5, 6, 7}},
+		},
+	}
+	svc := NewStatsService(repo, schedules, 0)
+
+	if _, err := svc.Turnaround(context.Background(), "2026-04-01", "2026-04-30", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	wallClock := monday.Sub(friday).Seconds()
+	tests := []struct {
+		name   string
+		sample []domain.TurnaroundSample
+		index  int
+		want   float64
+	}{
+		{"merge by team schedule", repo.rankedMerged, 0, (2 * time.Hour).Seconds()},
+		{"merge without a team", repo.rankedMerged, 1, wallClock},
+		{"verdict by team schedule", repo.rankedVerdicts, 0, (2 * time.Hour).Seconds()},
+		{"verdict by reviewer schedule", repo.rankedVerdicts, 1, wallClock},
+	}
+	for _, tt := range tests {
+		if len(tt.sample) <= tt.index {
+			t.Fatalf("%s: sample not ranked", tt.name)
+		}
+		if got := tt.sample[tt.index].Seconds; got != tt.want {
+			t.Errorf("%s: seconds = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
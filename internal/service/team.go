@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
 	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -12,6 +14,7 @@ import (
 type TeamService interface {
 	CreateTeam(ctx context.Context, team *domain.Team) (*domain.Team, error)
 	GetTeamByName(ctx context.Context, name string) (*domain.Team, error)
+	SetReviewSLA(ctx context.Context, name string, minutes *int) (*domain.Team, error)
 }
 
 type teamService struct {
@@ -25,6 +28,15 @@ func NewTeamService(teamRepo repository.TeamRepository) TeamService {
 }
 
 func (s *teamService) CreateTeam(ctx context.Context, team *domain.Team) (*domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.CreateTeam")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", team.TeamName)
+
+	if team.ReviewSLAMinutes != nil && *team.ReviewSLAMinutes <= 0 {
+		return nil, badRequest("review_sla_minutes must be positive")
+	}
+
 	err := s.teamRepo.Create(ctx, team)
 	if err != nil {
 
@@ -47,6 +59,11 @@ func (s *teamService) CreateTeam(ctx context.Context, team *domain.Team) (*domai
 }
 
 func (s *teamService) GetTeamByName(ctx context.Context, name string) (*domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetTeamByName")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", name)
+
 	team, err := s.teamRepo.GetByName(ctx, name)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -62,3 +79,28 @@ func (s *teamService) GetTeamByName(ctx context.Context, name string) (*domain.T
 
 	return team, nil
 }
+
+func (s *teamService) SetReviewSLA(ctx context.Context, name string, minutes *int) (*domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.SetReviewSLA")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "team_name", name)
+
+	if minutes != nil && *minutes <= 0 {
+		return nil, badRequest("review_sla_minutes must be positive")
+	}
+
+	if err := s.teamRepo.SetReviewSLA(ctx, name, minutes); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "team not found",
+				},
+			}
+		}
+		return nil, err
+	}
+
+	return s.GetTeamByName(ctx, name)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
 	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -24,6 +26,11 @@ func NewUserService(userRepo repository.UserRepository) UserService {
 }
 
 func (s *userService) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetIsActive")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "user_id", userID)
+
 	user, err := s.userRepo.SetIsActive(ctx, userID, isActive)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, pgx.ErrNoRows) {
@@ -41,6 +48,11 @@ func (s *userService) SetIsActive(ctx context.Context, userID string, isActive b
 }
 
 func (s *userService) GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserReviews")
+	defer span.End()
+
+	logger.AddAttrs(ctx, "user_id", userID)
+
 	prs, err := s.userRepo.GetPullRequests(ctx, userID)
 	if err != nil {
 		return nil, err
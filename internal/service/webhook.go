@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+	"github.com/Unitazavr/AvitoPR/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	ListDeadLetters(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+var webhookEventTypes = map[domain.EventType]struct{}{
+	domain.EventPRCreated:          {},
+	domain.EventReviewerAssigned:   {},
+	domain.EventReviewerReassigned: {},
+	domain.EventPRMerged:           {},
+	domain.EventUserDeactivated:    {},
+	domain.EventUserActivated:      {},
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, badRequest("url must be an absolute http(s) URL")
+	}
+
+	if len(sub.EventTypes) == 0 {
+		return nil, badRequest("event_types must not be empty")
+	}
+	for _, t := range sub.EventTypes {
+		if _, ok := webhookEventTypes[t]; !ok {
+			return nil, badRequest("unknown event type " + string(t))
+		}
+	}
+
+	// Если секрет не передан, генерируем его и отдаём один раз в ответе
+	if sub.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		sub.Secret = hex.EncodeToString(secret)
+	}
+
+	if err := s.webhookRepo.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListWebhooks")
+	defer span.End()
+
+	return s.webhookRepo.List(ctx)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	err := s.webhookRepo.Delete(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || isInvalidUUID(err) {
+			return &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "webhook not found",
+				},
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeadLetters(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListDeadLetters")
+	defer span.End()
+
+	return s.webhookRepo.ListDead(ctx, webhookID)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	delivery, err := s.webhookRepo.Redeliver(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ErrorResponse{
+				ErrorContent: domain.ErrorBody{
+					Code:    domain.ErrCodeNotFound,
+					Message: "dead-letter delivery not found",
+				},
+			}
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func badRequest(message string) error {
+	return &domain.ErrorResponse{
+		ErrorContent: domain.ErrorBody{
+			Code:    domain.ErrCodeBadRequest,
+			Message: message,
+		},
+	}
+}
+
+// isInvalidUUID - Postgres не смог разобрать идентификатор
+func isInvalidUUID(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22P02"
+}
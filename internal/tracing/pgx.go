@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ограничение длины SQL в атрибуте span
+const maxStatementLength = 2048
+
+// QueryTracer создаёт span на каждый запрос pgx
+type QueryTracer struct{}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+type spanKey struct{}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	statement := strings.Join(strings.Fields(data.SQL), " ")
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+
+	ctx, span := Start(ctx, "pgx.query "+operation(statement),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", statement),
+		attribute.Int("db.args_count", len(data.Args)),
+	)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+}
+
+// operation возвращает первое слово запроса (SELECT, INSERT, ...) для имени span
+func operation(statement string) string {
+	op, _, _ := strings.Cut(statement, " ")
+	return strings.ToUpper(op)
+}
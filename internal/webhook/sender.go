@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/logger"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	batchSize = 50
+	// Время, на которое доставка захватывается одним отправщиком
+	claimLease = time.Minute
+	maxBackoff = time.Hour
+)
+
+// Body - тело вебхука, которое получает подписчик
+type Body struct {
+	EventID   int64            `json:"event_id"`
+	EventType domain.EventType `json:"event_type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      json.RawMessage  `json:"data"`
+}
+
+// Sender ставит события в очередь доставок и отправляет их подписчикам
+type Sender struct {
+	repo        repository.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	maxAttempts int
+	baseBackoff time.Duration
+}
+
+func NewSender(repo repository.WebhookRepository, client *http.Client, interval time.Duration, maxAttempts int, baseBackoff time.Duration) *Sender {
+	return &Sender{
+		repo:        repo,
+		client:      client,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+	}
+}
+
+// Enqueue - обработчик шины событий, создаёт доставки для подходящих подписок
+func (s *Sender) Enqueue(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(Body{
+		EventID:   event.ID,
+		EventType: event.Type,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.Enqueue(ctx, event, body)
+}
+
+// Run блокируется до отмены ctx
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sendPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sender) sendPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimedAt := time.Now()
+		pending, err := s.repo.Claim(ctx, batchSize, claimLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Error("webhook claim failed", "error", err)
+			}
+			return
+		}
+
+		for _, p := range pending {
+			// Остаток пачки снова станет доступен, когда истечёт захват:
+			// иначе другой экземпляр перезахватит его и отправит повторно
+			if time.Since(claimedAt) > claimLease/2 || ctx.Err() != nil {
+				return
+			}
+			s.deliver(ctx, p)
+		}
+
+		if len(pending) < batchSize {
+			return
+		}
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, p repository.PendingDelivery) {
+	statusCode, err := s.post(ctx, p)
+	if err == nil {
+		if err := s.repo.MarkDelivered(ctx, p.Delivery.DeliveryID, statusCode); err != nil {
+			logger.FromContext(ctx).Error("webhook mark delivered failed", "delivery_id", p.Delivery.DeliveryID, "error", err)
+		}
+		return
+	}
+
+	// Попытки считаются с единицы: текущая ещё не учтена в p.Delivery.Attempts
+	attempt := p.Delivery.Attempts + 1
+	var nextAttemptAt *time.Time
+	if attempt < s.maxAttempts {
+		next := time.Now().Add(s.backoff(attempt))
+		nextAttemptAt = &next
+	}
+
+	logger.FromContext(ctx).Warn("webhook delivery failed",
+		"delivery_id", p.Delivery.DeliveryID,
+		"webhook_id", p.Delivery.WebhookID,
+		"attempt", attempt,
+		"dead", nextAttemptAt == nil,
+		"error", err,
+	)
+	if err := s.repo.MarkFailed(ctx, p.Delivery.DeliveryID, statusCode, err.Error(), nextAttemptAt); err != nil {
+		logger.FromContext(ctx).Error("webhook mark failed failed", "delivery_id", p.Delivery.DeliveryID, "error", err)
+	}
+}
+
+func (s *Sender) post(ctx context.Context, p repository.PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(p.Delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(p.Delivery.EventType))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(p.Delivery.DeliveryID, 10))
+	req.Header.Set(SignatureHeader, Sign(p.Secret, p.Delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff - экспоненциальная задержка перед попыткой attempt+1
+func (s *Sender) backoff(attempt int) time.Duration {
+	d := s.baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// Sign возвращает значение заголовка подписи: sha256=<hex HMAC-SHA256 тела>
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
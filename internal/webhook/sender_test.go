@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unitazavr/AvitoPR/internal/domain"
+	"github.com/Unitazavr/AvitoPR/internal/repository"
+)
+
+// fakeRepo отдаёт доставки один раз и запоминает результаты попыток
+type fakeRepo struct {
+	repository.WebhookRepository
+
+	mu        sync.Mutex
+	pending   []repository.PendingDelivery
+	delivered map[int64]int
+	failed    map[int64]failedAttempt
+}
+
+type failedAttempt struct {
+	statusCode    int
+	errMsg        string
+	nextAttemptAt *time.Time
+}
+
+func newFakeRepo(pending ...repository.PendingDelivery) *fakeRepo {
+	return &fakeRepo{
+		pending:   pending,
+		delivered: map[int64]int{},
+		failed:    map[int64]failedAttempt{},
+	}
+}
+
+func (r *fakeRepo) Claim(_ context.Context, limit int, _ time.Duration) ([]repository.PendingDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := min(limit, len(r.pending))
+	claimed := r.pending[:n]
+	r.pending = r.pending[n:]
+	return claimed, nil
+}
+
+func (r *fakeRepo) MarkDelivered(_ context.Context, deliveryID int64, statusCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivered[deliveryID] = statusCode
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(_ context.Context, deliveryID int64, statusCode int, errMsg string, nextAttemptAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed[deliveryID] = failedAttempt{statusCode: statusCode, errMsg: errMsg, nextAttemptAt: nextAttemptAt}
+	return nil
+}
+
+func pendingDelivery(id int64, url string, attempts int) repository.PendingDelivery {
+	return repository.PendingDelivery{
+		Delivery: domain.WebhookDelivery{
+			DeliveryID: id,
+			EventType:  domain.EventPRCreated,
+			Payload:    []byte(`{"event_id":1,"event_type":"PR_CREATED"}`),
+			Status:     domain.WebhookDeliveryPending,
+			Attempts:   attempts,
+		},
+		URL:    url,
+		Secret: "s3cret",
+	}
+}
+
+func TestSenderSignsDelivery(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	p := pendingDelivery(7, receiver.URL, 0)
+	repo := newFakeRepo(p)
+	sender := NewSender(repo, receiver.Client(), time.Second, 3, time.Second)
+	sender.sendPending(context.Background())
+
+	r := <-got
+	if string(r.body) != string(p.Delivery.Payload) {
+		t.Fatalf("body = %s, want %s", r.body, p.Delivery.Payload)
+	}
+
+	// Подпись считается независимо от Sign, как это сделал бы получатель
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+	mac.Write(r.body)
+	wantSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if sig := r.header.Get(SignatureHeader); sig != wantSignature {
+		t.Errorf("%s = %q, want %q", SignatureHeader, sig, wantSignature)
+	}
+	if ev := r.header.Get(EventHeader); ev != string(domain.EventPRCreated) {
+		t.Errorf("%s = %q, want %q", EventHeader, ev, domain.EventPRCreated)
+	}
+	if id := r.header.Get(DeliveryHeader); id != strconv.FormatInt(p.Delivery.DeliveryID, 10) {
+		t.Errorf("%s = %q, want 7", DeliveryHeader, id)
+	}
+	if ct := r.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	if status, ok := repo.delivered[7]; !ok || status != http.StatusAccepted {
+		t.Errorf("delivery not marked delivered with 202: %v", repo.delivered)
+	}
+	if len(repo.failed) != 0 {
+		t.Errorf("unexpected failures: %v", repo.failed)
+	}
+}
+
+func TestSenderRetriesWithBackoffThenDead(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	const maxAttempts = 3
+	repo := newFakeRepo(
+		pendingDelivery(1, receiver.URL, 0),
+		pendingDelivery(2, receiver.URL, 1),
+		pendingDelivery(3, receiver.URL, maxAttempts-1),
+	)
+	sender := NewSender(repo, receiver.Client(), time.Second, maxAttempts, 10*time.Second)
+
+	before := time.Now()
+	sender.sendPending(context.Background())
+	after := time.Now()
+
+	tests := []struct {
+		id      int64
+		backoff time.Duration
+		dead    bool
+	}{
+		{id: 1, backoff: 10 * time.Second},
+		{id: 2, backoff: 20 * time.Second},
+		{id: 3, dead: true},
+	}
+	for _, tt := range tests {
+		f, ok := repo.failed[tt.id]
+		if !ok {
+			t.Fatalf("delivery %d not marked failed", tt.id)
+		}
+		if f.statusCode != http.StatusInternalServerError {
+			t.Errorf("delivery %d: status = %d, want 500", tt.id, f.statusCode)
+		}
+		if tt.dead {
+			if f.nextAttemptAt != nil {
+				t.Errorf("delivery %d: next attempt at %v, want dead", tt.id, *f.nextAttemptAt)
+			}
+			continue
+		}
+		if f.nextAttemptAt == nil {
+			t.Fatalf("delivery %d: marked dead, want retry", tt.id)
+		}
+		if f.nextAttemptAt.Before(before.Add(tt.backoff)) || f.nextAttemptAt.After(after.Add(tt.backoff)) {
+			t.Errorf("delivery %d: next attempt in %v, want %v", tt.id, f.nextAttemptAt.Sub(before), tt.backoff)
+		}
+	}
+	if len(repo.delivered) != 0 {
+		t.Errorf("unexpected deliveries: %v", repo.delivered)
+	}
+}
+
+func TestSenderBackoffIsCapped(t *testing.T) {
+	sender := NewSender(nil, nil, time.Second, 20, 10*time.Second)
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{8, 1280 * time.Second},
+		{9, 2560 * time.Second},
+		{10, maxBackoff},
+		{30, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := sender.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}